@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"HarborArk/config"
+	"HarborArk/internal/certs"
+	"HarborArk/internal/i18n"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// 本地 CA 目录下的文件名
+const (
+	caCertFile     = "ca.crt"
+	caKeyFile      = "ca.key"
+	serverCertFile = "server.crt"
+	serverKeyFile  = "server.key"
+)
+
+func init() {
+	// 创建 cert 主命令
+	certCmd := &cobra.Command{
+		Use:   "cert",
+		Short: "cli.cert.short",
+		Long:  "cli.cert.long",
+	}
+
+	// 创建 init 子命令
+	initCmd := &cobra.Command{
+		Use:           "init",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Short:         "cli.cert.init.short",
+		Long:          "cli.cert.init.long",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, _ := cmd.Flags().GetString("dir")
+			hosts, _ := cmd.Flags().GetStringSlice("host")
+			caValidity, _ := cmd.Flags().GetDuration("ca-validity")
+			validity, _ := cmd.Flags().GetDuration("validity")
+			force, _ := cmd.Flags().GetBool("force")
+			install, _ := cmd.Flags().GetBool("install")
+			configFile, _, err := config.FilePath(configFlags(cmd))
+			if err != nil {
+				return err
+			}
+			if len(hosts) == 0 {
+				hosts = defaultCertHosts()
+			}
+			if err := initCerts(dir, hosts, caValidity, validity, force, install, configFile); err != nil {
+				return err
+			}
+			recordConfigChange(cmd, "certs/server", map[string]string{
+				"hosts":   strings.Join(hosts, ","),
+				"install": strconv.FormatBool(install),
+			})
+			return nil
+		},
+	}
+
+	// 创建 export-ca 子命令
+	exportCmd := &cobra.Command{
+		Use:           "export-ca",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Short:         "cli.cert.export.short",
+		Long:          "cli.cert.export.long",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, _ := cmd.Flags().GetString("dir")
+			output, _ := cmd.Flags().GetString("output")
+			format, _ := cmd.Flags().GetString("format")
+			return exportCA(dir, output, format)
+		},
+	}
+
+	// 创建 client 子命令
+	clientCmd := &cobra.Command{
+		Use:           "client <common-name>",
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Short:         "cli.cert.client.short",
+		Long:          "cli.cert.client.long",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, _ := cmd.Flags().GetString("dir")
+			validity, _ := cmd.Flags().GetDuration("validity")
+			force, _ := cmd.Flags().GetBool("force")
+			if err := issueClientCert(dir, args[0], validity, force); err != nil {
+				return err
+			}
+			recordConfigChange(cmd, "certs/clients/"+args[0], nil)
+			return nil
+		},
+	}
+
+	// 添加标志
+	certCmd.PersistentFlags().String("dir", "config/certs", "cli.cert.flags.dir")
+	initCmd.Flags().StringSlice("host", nil, "cli.cert.init.flags.host")
+	initCmd.Flags().Duration("ca-validity", 10*365*24*time.Hour, "cli.cert.init.flags.ca_validity")
+	initCmd.Flags().Duration("validity", 825*24*time.Hour, "cli.cert.init.flags.validity")
+	initCmd.Flags().BoolP("force", "f", false, "cli.cert.init.flags.force")
+	initCmd.Flags().Bool("install", true, "cli.cert.init.flags.install")
+	exportCmd.Flags().StringP("output", "o", "", "cli.cert.export.flags.output")
+	exportCmd.Flags().String("format", "pem", "cli.cert.export.flags.format")
+	clientCmd.Flags().Duration("validity", 365*24*time.Hour, "cli.cert.client.flags.validity")
+	clientCmd.Flags().BoolP("force", "f", false, "cli.cert.client.flags.force")
+
+	// 添加子命令
+	certCmd.AddCommand(initCmd)
+	certCmd.AddCommand(exportCmd)
+	certCmd.AddCommand(clientCmd)
+
+	// 添加到根命令
+	rootCmd.AddCommand(certCmd)
+}
+
+// initCerts 创建或复用本地 CA，签发服务端证书并按需写入配置
+func initCerts(dir string, hosts []string, caValidity, validity time.Duration, force, install bool, configFile string) error {
+	serverCert := filepath.Join(dir, serverCertFile)
+	serverKey := filepath.Join(dir, serverKeyFile)
+	if _, err := os.Stat(serverCert); err == nil && !force {
+		return i18n.Errorf("cli.cert.init.exists", serverCert)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return i18n.Errorf("cli.cert.init.mkdir_failed", err)
+	}
+
+	ca, err := loadOrCreateCA(dir, caValidity)
+	if err != nil {
+		return err
+	}
+
+	certPEM, keyPEM, err := ca.Issue(hosts, validity)
+	if err != nil {
+		return err
+	}
+	// 先写私钥再写证书，运行中的服务在证书文件变化时热加载
+	if err := os.WriteFile(serverKey, keyPEM, 0600); err != nil {
+		return i18n.Errorf("cli.cert.init.write_key_failed", err)
+	}
+	if err := os.WriteFile(serverCert, certPEM, 0644); err != nil {
+		return i18n.Errorf("cli.cert.init.write_cert_failed", err)
+	}
+	fmt.Println("✅", i18n.T("cli.cert.init.issued", serverCert))
+	fmt.Println("  ", i18n.T("cli.cert.init.hosts", hosts))
+
+	if install {
+		if err := config.UpdateFile(configFile, map[string]interface{}{
+			"server.tls.enabled":  true,
+			"server.tls.certFile": filepath.ToSlash(serverCert),
+			"server.tls.keyFile":  filepath.ToSlash(serverKey),
+		}); err != nil {
+			return err
+		}
+		fmt.Println("⚙️ ", i18n.T("cli.cert.init.installed", configFile))
+	}
+
+	fmt.Println("\n📱", i18n.T("cli.cert.init.install_ca"))
+	fmt.Printf("   %s cert export-ca -o harborark-ca.crt\n", rootCmd.Name())
+	fmt.Println("  ", i18n.T("cli.cert.init.fingerprint", ca.Fingerprint()))
+	return nil
+}
+
+// loadOrCreateCA 复用目录中已有的 CA，避免重新签发服务端证书后客户端需要重新安装根证书
+func loadOrCreateCA(dir string, validity time.Duration) (*certs.CA, error) {
+	certFile := filepath.Join(dir, caCertFile)
+	keyFile := filepath.Join(dir, caKeyFile)
+	if _, err := os.Stat(certFile); err == nil {
+		ca, err := certs.LoadCA(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		if time.Now().After(ca.Cert.NotAfter) {
+			return nil, i18n.Errorf("cli.cert.ca.expired", ca.Cert.NotAfter.Format(time.RFC3339), dir)
+		}
+		fmt.Println("🔑", i18n.T("cli.cert.ca.reused", certFile))
+		return ca, nil
+	}
+
+	hostname, _ := os.Hostname()
+	ca, err := certs.NewCA(fmt.Sprintf("HarborArk Local CA (%s)", hostname), validity)
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := ca.KeyPEM()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(keyFile, keyPEM, 0600); err != nil {
+		return nil, i18n.Errorf("cli.cert.ca.write_key_failed", err)
+	}
+	if err := os.WriteFile(certFile, ca.CertPEM(), 0644); err != nil {
+		return nil, i18n.Errorf("cli.cert.ca.write_cert_failed", err)
+	}
+	fmt.Println("🔑", i18n.T("cli.cert.ca.created", certFile))
+	return ca, nil
+}
+
+// issueClientCert 使用本地 CA 签发客户端证书，写入 <dir>/clients/<name>.crt 与 .key
+func issueClientCert(dir, commonName string, validity time.Duration, force bool) error {
+	caCert, caKey := filepath.Join(dir, caCertFile), filepath.Join(dir, caKeyFile)
+	if _, err := os.Stat(caCert); err != nil {
+		return i18n.Errorf("cli.cert.client.no_ca", dir)
+	}
+	ca, err := certs.LoadCA(caCert, caKey)
+	if err != nil {
+		return err
+	}
+
+	clientDir := filepath.Join(dir, "clients")
+	certFile := filepath.Join(clientDir, commonName+".crt")
+	keyFile := filepath.Join(clientDir, commonName+".key")
+	if _, err := os.Stat(certFile); err == nil && !force {
+		return i18n.Errorf("cli.cert.client.exists", certFile)
+	}
+	if err := os.MkdirAll(clientDir, 0700); err != nil {
+		return i18n.Errorf("cli.cert.init.mkdir_failed", err)
+	}
+	certPEM, keyPEM, err := ca.IssueClient(commonName, validity)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(keyFile, keyPEM, 0600); err != nil {
+		return i18n.Errorf("cli.cert.init.write_key_failed", err)
+	}
+	if err := os.WriteFile(certFile, certPEM, 0644); err != nil {
+		return i18n.Errorf("cli.cert.init.write_cert_failed", err)
+	}
+	fmt.Println("✅", i18n.T("cli.cert.client.issued", certFile, keyFile))
+	fmt.Println("\n⚙️ ", i18n.T("cli.cert.client.configure"))
+	fmt.Printf("   server.tls.clientAuth: require\n   server.tls.clientCAFile: %s\n", filepath.ToSlash(caCert))
+	fmt.Printf("   server.tls.clientUsers: [{commonName: %s, user: <name>, role: admin}]\n", commonName)
+	return nil
+}
+
+// exportCA 以 PEM 或 DER 格式导出 CA 证书
+func exportCA(dir, output, format string) error {
+	data, err := os.ReadFile(filepath.Join(dir, caCertFile))
+	if err != nil {
+		return i18n.Errorf("cli.cert.export.read_failed", err)
+	}
+	switch format {
+	case "pem":
+	case "der":
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return errors.New(i18n.T("cli.cert.export.invalid_ca"))
+		}
+		data = block.Bytes
+	default:
+		return i18n.Errorf("cli.cert.export.invalid_format", format)
+	}
+
+	if output == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		return i18n.Errorf("cli.cert.export.write_failed", output, err)
+	}
+	fmt.Println("✅", i18n.T("cli.cert.export.done", output))
+	return nil
+}
+
+// defaultCertHosts 默认包含 localhost、本机名以及本机所有非回环地址
+func defaultCertHosts() []string {
+	hosts := []string{"localhost", "127.0.0.1", "::1"}
+	if hostname, err := os.Hostname(); err == nil && hostname != "" && hostname != "localhost" {
+		hosts = append(hosts, hostname)
+	}
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return hosts
+	}
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLoopback() || ipNet.IP.IsLinkLocalUnicast() {
+			continue
+		}
+		hosts = append(hosts, ipNet.IP.String())
+	}
+	return hosts
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"HarborArk/config"
+	"HarborArk/internal/i18n"
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// adminClient 调用运行中服务的管理 API
+type adminClient struct {
+	base   string
+	client *http.Client
+}
+
+// addClientFlags 为需要连接运行中服务的命令添加 --server、--cacert 以及客户端证书标志
+func addClientFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().String("server", "", "cli.client.flags.server")
+	cmd.PersistentFlags().String("cacert", "", "cli.client.flags.cacert")
+	cmd.PersistentFlags().String("cert", "", "cli.client.flags.cert")
+	cmd.PersistentFlags().String("key", "", "cli.client.flags.key")
+}
+
+// newAdminClient 根据 --server、--cacert 或配置文件创建客户端，管理接口需要 --cert 与 --key 指定 admin 角色的客户端证书
+func newAdminClient(cmd *cobra.Command) (*adminClient, error) {
+	server, _ := cmd.Flags().GetString("server")
+	caFile, _ := cmd.Flags().GetString("cacert")
+	certFile, _ := cmd.Flags().GetString("cert")
+	keyFile, _ := cmd.Flags().GetString("key")
+	if server == "" {
+		if err := loadConfig(cmd, nil); err != nil {
+			return nil, err
+		}
+		serverConfig := config.GetServerConfig()
+		scheme := "http"
+		if serverConfig.TLS.Enabled {
+			scheme = "https"
+		}
+		server = scheme + "://localhost:" + serverConfig.Port
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	tlsConfig := &tls.Config{}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, i18n.Errorf("cli.client.read_ca_failed", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, i18n.Errorf("cli.client.invalid_ca", caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, i18n.Errorf("cli.client.load_cert_failed", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	transport.TLSClientConfig = tlsConfig
+	return &adminClient{
+		base:   server + "/api/v1/admin",
+		client: &http.Client{Transport: transport, Timeout: 10 * time.Second},
+	}, nil
+}
+
+// stream 读取 Server-Sent Events，每个事件调用一次 fn，连接结束或 fn 返回错误时返回
+func (a *adminClient) stream(path string, fn func(event, data string) error) error {
+	req, err := http.NewRequest(http.MethodGet, a.base+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Accept-Language", i18n.Language())
+	// 长连接不设置整体超时
+	client := &http.Client{Transport: a.client.Transport}
+	resp, err := client.Do(req)
+	if err != nil {
+		return i18n.Errorf("cli.client.connect_failed", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		var result struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&result)
+		return fmt.Errorf("%s (HTTP %d)", result.Message, resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64<<10), 4<<20)
+	var event, data string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if data != "" {
+				if err := fn(event, data); err != nil {
+					return err
+				}
+			}
+			event, data = "", ""
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data += strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " ")
+		}
+	}
+	return scanner.Err()
+}
+
+// do 发送请求并将响应中的 data 解析到 out，非 2xx 响应返回其中的 message
+func (a *adminClient) do(method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, a.base+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	// 服务端的错误信息使用与命令行相同的语言
+	req.Header.Set("Accept-Language", i18n.Language())
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return i18n.Errorf("cli.client.connect_failed", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Message string          `json:"message"`
+		Error   string          `json:"error"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return i18n.Errorf("cli.client.decode_failed", resp.StatusCode, err)
+	}
+	if resp.StatusCode >= 300 {
+		if result.Error != "" {
+			return fmt.Errorf("%s: %s", result.Message, result.Error)
+		}
+		return fmt.Errorf("%s (HTTP %d)", result.Message, resp.StatusCode)
+	}
+	if out != nil && len(result.Data) > 0 {
+		return json.Unmarshal(result.Data, out)
+	}
+	return nil
+}
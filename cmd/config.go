@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"HarborArk/config"
+	"HarborArk/internal/i18n"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	// 创建 config 主命令
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "cli.config.short",
+		Long:  "cli.config.long",
+	}
+
+	// 创建 show 子命令
+	showCmd := &cobra.Command{
+		Use:           "show",
+		Short:         "cli.config.show.short",
+		Long:          "cli.config.show.long",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sources, _ := cmd.Flags().GetBool("sources")
+			return showConfig(cmd, sources)
+		},
+	}
+
+	// 创建 validate 子命令
+	validateCmd := &cobra.Command{
+		Use:           "validate [file]",
+		Short:         "cli.config.validate.short",
+		Long:          "cli.config.validate.long",
+		Args:          cobra.MaximumNArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, profile, err := config.FilePath(configFlags(cmd))
+			if err != nil {
+				return err
+			}
+			if len(args) == 1 {
+				file = args[0]
+			}
+			return validateConfig(file, profile)
+		},
+	}
+
+	// 创建 init 子命令
+	initCmd := &cobra.Command{
+		Use:           "init",
+		Short:         "cli.config.init.short",
+		Long:          "cli.config.init.long",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, profile, err := config.FilePath(configFlags(cmd))
+			if err != nil {
+				return err
+			}
+			if output, _ := cmd.Flags().GetString("output"); output != "" {
+				file = output
+			}
+			force, _ := cmd.Flags().GetBool("force")
+			return initConfig(file, profile, force)
+		},
+	}
+
+	// 创建 diff 子命令
+	diffCmd := &cobra.Command{
+		Use:           "diff <a> <b>",
+		Short:         "cli.config.diff.short",
+		Long:          "cli.config.diff.long",
+		Args:          cobra.ExactArgs(2),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, profile, err := config.FilePath(configFlags(cmd))
+			if err != nil {
+				return err
+			}
+			return diffConfig(args[0], args[1], profile)
+		},
+	}
+
+	// 添加标志
+	showCmd.Flags().Bool("sources", true, "cli.config.show.flags.sources")
+	initCmd.Flags().StringP("output", "o", "", "cli.config.init.flags.output")
+	initCmd.Flags().BoolP("force", "f", false, "cli.config.init.flags.force")
+
+	// 添加子命令
+	configCmd.AddCommand(showCmd)
+	configCmd.AddCommand(validateCmd)
+	configCmd.AddCommand(initCmd)
+	configCmd.AddCommand(diffCmd)
+
+	// 添加到根命令
+	rootCmd.AddCommand(configCmd)
+}
+
+// showConfig 输出生效的配置
+func showConfig(cmd *cobra.Command, sources bool) error {
+	if err := loadConfig(cmd, nil); err != nil {
+		return err
+	}
+	opts := config.RenderOptions{Redact: true, Refs: config.Refs()}
+	if sources {
+		opts.Source = config.Source
+	}
+	data, err := config.Render(config.Get(), opts)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("# %s\n# %s\n", i18n.T("cli.config.show.file", config.File()), i18n.T("cli.config.show.profile", config.Profile()))
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+// validateConfig 校验配置文件并列出全部问题
+func validateConfig(file string, profile config.Runmode) error {
+	if _, _, err := config.LoadFile(file, profile); err != nil {
+		return err
+	}
+	fmt.Println("✅", i18n.T("cli.config.validate.ok", file))
+	return nil
+}
+
+// initConfig 生成带注释的默认配置文件
+func initConfig(file string, profile config.Runmode, force bool) error {
+	data, err := config.Render(config.ProfileDefault(profile), config.RenderOptions{Comments: true})
+	if err != nil {
+		return err
+	}
+	header := "# " + i18n.T("cli.config.init.header", profile) + "\n# " + i18n.T("cli.config.init.precedence") + "\n\n"
+	data = append([]byte(header), data...)
+
+	if file == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if _, err := os.Stat(file); err == nil && !force {
+		return i18n.Errorf("cli.config.init.exists", file)
+	}
+	if err := os.WriteFile(file, data, 0644); err != nil {
+		return i18n.Errorf("cli.config.init.write_failed", err)
+	}
+	fmt.Println("✅", i18n.T("cli.config.init.done", file))
+	return nil
+}
+
+// diffConfig 比较两个配置文件的实际配置
+func diffConfig(fileA, fileB string, profile config.Runmode) error {
+	a, refsA, err := loadForDiff(fileA, profile)
+	if err != nil {
+		return err
+	}
+	b, refsB, err := loadForDiff(fileB, profile)
+	if err != nil {
+		return err
+	}
+
+	settingsB := config.Flatten(b, refsB)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\t%s\t%s\n", i18n.T("cli.config.diff.key"), fileA, fileB)
+	changed := 0
+	for i, sa := range config.Flatten(a, refsA) {
+		sb := settingsB[i]
+		if sa.Value == sb.Value {
+			continue
+		}
+		changed++
+		valueA, valueB := sa.Display, sb.Display
+		if config.IsSecret(sa.Key) || refsA.Has(sa.Key) || refsB.Has(sa.Key) {
+			valueA, valueB = "******", "******"+i18n.T("cli.config.diff.changed")
+		} else if valueA == valueB {
+			// 仅其中的敏感信息不同
+			valueB += i18n.T("cli.config.diff.secret_changed")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", sa.Key, valueA, valueB)
+	}
+	if changed == 0 {
+		fmt.Println(i18n.T("cli.config.diff.same"))
+		return nil
+	}
+	return w.Flush()
+}
+
+// loadForDiff 加载配置文件，校验问题只提示不中断比较
+func loadForDiff(file string, profile config.Runmode) (*config.AppConfig, config.References, error) {
+	cfg, refs, err := config.LoadFile(file, profile)
+	var verr *config.ValidationError
+	if errors.As(err, &verr) && cfg != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n\n", err)
+		return cfg, refs, nil
+	}
+	return cfg, refs, err
+}
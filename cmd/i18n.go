@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"HarborArk/config"
+	"HarborArk/internal/i18n"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	// 创建 i18n 主命令
+	i18nCmd := &cobra.Command{
+		Use:   "i18n",
+		Short: "cli.i18n.short",
+	}
+
+	// 创建 check 子命令
+	checkCmd := &cobra.Command{
+		Use:           "check",
+		Short:         "cli.i18n.check.short",
+		Long:          "cli.i18n.check.long",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			source, _ := cmd.Flags().GetString("source")
+			return checkMessages(source)
+		},
+	}
+
+	checkCmd.Flags().String("source", ".", "cli.i18n.check.flags.source")
+
+	i18nCmd.AddCommand(checkCmd)
+	rootCmd.AddCommand(i18nCmd)
+}
+
+// messageKeyPattern 匹配源码中形如消息键的字符串字面量
+var messageKeyPattern = regexp.MustCompile(`"((?:api|cli|errors|validation)(?:\.[a-z0-9_]+)+)"`)
+
+// checkMessages 检查语言包之间的键与占位符是否一致，以及源码中使用的消息键是否存在
+func checkMessages(source string) error {
+	problems := i18n.Check()
+
+	// 配置项说明与配置校验规则的键由配置结构生成，不以字面量出现在源码中
+	for _, key := range config.MessageKeys() {
+		if _, ok := i18n.Lookup(i18n.Languages[0], key); !ok {
+			problems = append(problems, i18n.T("cli.i18n.check.unknown_key", key, "HarborArk/config"))
+		}
+	}
+
+	if info, err := os.Stat(source); err == nil && info.IsDir() {
+		known := map[string]bool{}
+		for _, key := range i18n.Keys(i18n.Languages[0]) {
+			known[key] = true
+			// 动态拼接的键以前缀形式出现在源码中
+			for prefix := key; strings.Contains(prefix, "."); {
+				prefix = prefix[:strings.LastIndex(prefix, ".")]
+				known[prefix] = true
+			}
+		}
+		missing := map[string][]string{}
+		err := filepath.WalkDir(source, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() && (d.Name() == "vendor" || d.Name() == "docs" || strings.HasPrefix(d.Name(), ".")) && path != source {
+				return filepath.SkipDir
+			}
+			if d.IsDir() || !strings.HasSuffix(path, ".go") {
+				return nil
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			for _, m := range messageKeyPattern.FindAllStringSubmatch(string(data), -1) {
+				if !known[m[1]] {
+					missing[m[1]] = append(missing[m[1]], path)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		keys := make([]string, 0, len(missing))
+		for key := range missing {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			problems = append(problems, i18n.T("cli.i18n.check.unknown_key", key, strings.Join(missing[key], ", ")))
+		}
+	}
+
+	if len(problems) > 0 {
+		for _, p := range problems {
+			fmt.Println("  " + p)
+		}
+		return i18n.Errorf("cli.i18n.check.failed", len(problems))
+	}
+	fmt.Println("✅", i18n.T("cli.i18n.check.ok", len(i18n.Keys(i18n.Languages[0])), strings.Join(i18n.Languages, ", ")))
+	return nil
+}
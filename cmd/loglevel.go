@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"HarborArk/internal/controller"
+	"HarborArk/internal/i18n"
+	"HarborArk/internal/logging"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	// 创建 log-level 主命令
+	logLevelCmd := &cobra.Command{
+		Use:   "log-level",
+		Short: "cli.loglevel.short",
+		Long:  "cli.loglevel.long",
+	}
+
+	// 创建 show 子命令
+	showCmd := &cobra.Command{
+		Use:           "show",
+		Short:         "cli.loglevel.show.short",
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newAdminClient(cmd)
+			if err != nil {
+				return err
+			}
+			var state controller.LoggingState
+			if err := client.do(http.MethodGet, "/logging", nil, &state); err != nil {
+				return err
+			}
+			printLoggingState(state)
+			return nil
+		},
+	}
+
+	// 创建 set 子命令
+	setCmd := &cobra.Command{
+		Use:           "set <level>",
+		Short:         "cli.loglevel.set.short",
+		Long:          "cli.loglevel.set.long",
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, _ := cmd.Flags().GetString("logger")
+			if _, err := logging.ParseLevel(args[0]); err != nil {
+				return i18n.Errorf("cli.loglevel.set.invalid_level", args[0])
+			}
+			client, err := newAdminClient(cmd)
+			if err != nil {
+				return err
+			}
+			path := "/logging/level"
+			if name != "" {
+				path = "/logging/loggers/" + url.PathEscape(name)
+			}
+			if err := client.do(http.MethodPut, path, controller.LogLevelRequest{Level: args[0]}, nil); err != nil {
+				return err
+			}
+			if name != "" {
+				fmt.Println("✅", i18n.T("cli.loglevel.set.logger_done", name, args[0]))
+			} else {
+				fmt.Println("✅", i18n.T("cli.loglevel.set.done", args[0]))
+			}
+			return nil
+		},
+	}
+
+	// 创建 reset 子命令
+	resetCmd := &cobra.Command{
+		Use:           "reset <logger>",
+		Short:         "cli.loglevel.reset.short",
+		Long:          "cli.loglevel.reset.long",
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newAdminClient(cmd)
+			if err != nil {
+				return err
+			}
+			if err := client.do(http.MethodDelete, "/logging/loggers/"+url.PathEscape(args[0]), nil, nil); err != nil {
+				return err
+			}
+			fmt.Println("✅", i18n.T("cli.loglevel.reset.done", args[0]))
+			return nil
+		},
+	}
+
+	// 创建 debug 子命令
+	debugCmd := &cobra.Command{
+		Use:           "debug",
+		Short:         "cli.loglevel.debug.short",
+		Long:          "cli.loglevel.debug.long",
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			user, _ := cmd.Flags().GetString("user")
+			path, _ := cmd.Flags().GetString("path")
+			duration, _ := cmd.Flags().GetDuration("for")
+			if user == "" && path == "" {
+				return errors.New(i18n.T("cli.loglevel.debug.target_required"))
+			}
+			client, err := newAdminClient(cmd)
+			if err != nil {
+				return err
+			}
+			var rule logging.DebugRule
+			req := controller.DebugRuleRequest{User: user, Path: path, Duration: duration.String()}
+			if err := client.do(http.MethodPost, "/logging/debug", req, &rule); err != nil {
+				return err
+			}
+			fmt.Println("🐛", i18n.T("cli.loglevel.debug.enabled", rule.ID))
+			fmt.Println("  ", i18n.T("cli.loglevel.debug.expires", rule.ExpiresAt.Local().Format(time.DateTime)))
+			fmt.Println("  ", i18n.T("cli.loglevel.debug.cancel_hint", rootCmd.Name()+" log-level cancel "+rule.ID))
+			return nil
+		},
+	}
+
+	// 创建 cancel 子命令
+	cancelCmd := &cobra.Command{
+		Use:           "cancel <id>",
+		Short:         "cli.loglevel.cancel.short",
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := newAdminClient(cmd)
+			if err != nil {
+				return err
+			}
+			if err := client.do(http.MethodDelete, "/logging/debug/"+url.PathEscape(args[0]), nil, nil); err != nil {
+				return err
+			}
+			fmt.Println("✅", i18n.T("cli.loglevel.cancel.done", args[0]))
+			return nil
+		},
+	}
+
+	// 添加标志
+	addClientFlags(logLevelCmd)
+	setCmd.Flags().String("logger", "", "cli.loglevel.set.flags.logger")
+	debugCmd.Flags().String("user", "", "cli.loglevel.debug.flags.user")
+	debugCmd.Flags().String("path", "", "cli.loglevel.debug.flags.path")
+	debugCmd.Flags().Duration("for", 10*time.Minute, "cli.loglevel.debug.flags.for")
+
+	// 添加子命令
+	logLevelCmd.AddCommand(showCmd)
+	logLevelCmd.AddCommand(setCmd)
+	logLevelCmd.AddCommand(resetCmd)
+	logLevelCmd.AddCommand(debugCmd)
+	logLevelCmd.AddCommand(cancelCmd)
+
+	// 添加到根命令
+	rootCmd.AddCommand(logLevelCmd)
+}
+
+// printLoggingState 输出日志级别状态
+func printLoggingState(state controller.LoggingState) {
+	fmt.Println(i18n.T("cli.loglevel.show.global", state.Level))
+
+	if len(state.Loggers) > 0 {
+		fmt.Println("\n" + i18n.T("cli.loglevel.show.loggers"))
+		names := make([]string, 0, len(state.Loggers))
+		for name := range state.Loggers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, name := range names {
+			fmt.Fprintf(w, "  %s\t%s\n", name, state.Loggers[name])
+		}
+		w.Flush()
+	}
+
+	if len(state.Debug) > 0 {
+		fmt.Println("\n" + i18n.T("cli.loglevel.show.debug"))
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "  ID\tUSER\tPATH\tEXPIRES")
+		for _, rule := range state.Debug {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", rule.ID, orDash(rule.User), orDash(rule.Path), rule.ExpiresAt.Local().Format(time.DateTime))
+		}
+		w.Flush()
+	}
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"HarborArk/config"
+	"HarborArk/internal/i18n"
+	"HarborArk/internal/service/logs"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// logsOptions logs 命令的过滤与输出选项
+type logsOptions struct {
+	since, until string
+	filter       logs.Filter
+	follow       bool
+	json         bool
+	local        bool
+}
+
+func init() {
+	// 创建 logs 命令
+	logsCmd := &cobra.Command{
+		Use:   "logs",
+		Short: "cli.logs.short",
+		Long:  "cli.logs.long",
+		Example: `  harborArk logs --since 1h --level warn
+  harborArk logs --path /api/v1/users -q timeout
+  harborArk logs --request-id 4f3c2a1b9d8e7f60
+  harborArk logs -f --level error`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var opts logsOptions
+			opts.since, _ = cmd.Flags().GetString("since")
+			opts.until, _ = cmd.Flags().GetString("until")
+			opts.filter.Level, _ = cmd.Flags().GetString("level")
+			opts.filter.Path, _ = cmd.Flags().GetString("path")
+			opts.filter.RequestID, _ = cmd.Flags().GetString("request-id")
+			opts.filter.Text, _ = cmd.Flags().GetString("grep")
+			opts.filter.Limit, _ = cmd.Flags().GetInt("limit")
+			opts.follow, _ = cmd.Flags().GetBool("follow")
+			opts.json, _ = cmd.Flags().GetBool("json")
+			opts.local, _ = cmd.Flags().GetBool("local")
+			if opts.local {
+				return localLogs(cmd, opts)
+			}
+			return remoteLogs(cmd, opts)
+		},
+	}
+
+	// 添加标志
+	addClientFlags(logsCmd)
+	logsCmd.Flags().String("since", "", "cli.logs.flags.since")
+	logsCmd.Flags().String("until", "", "cli.logs.flags.until")
+	logsCmd.Flags().String("level", "", "cli.logs.flags.level")
+	logsCmd.Flags().String("path", "", "cli.logs.flags.path")
+	logsCmd.Flags().String("request-id", "", "cli.logs.flags.request_id")
+	logsCmd.Flags().StringP("grep", "q", "", "cli.logs.flags.grep")
+	logsCmd.Flags().IntP("limit", "n", 100, "cli.logs.flags.limit")
+	logsCmd.Flags().BoolP("follow", "f", false, "cli.logs.flags.follow")
+	logsCmd.Flags().Bool("json", false, "cli.logs.flags.json")
+	logsCmd.Flags().Bool("local", false, "cli.logs.flags.local")
+
+	// 添加到根命令
+	rootCmd.AddCommand(logsCmd)
+}
+
+// remoteLogs 通过管理 API 查询或跟踪日志
+func remoteLogs(cmd *cobra.Command, opts logsOptions) error {
+	client, err := newAdminClient(cmd)
+	if err != nil {
+		return err
+	}
+	query := url.Values{}
+	for key, value := range map[string]string{
+		"since":      opts.since,
+		"until":      opts.until,
+		"level":      opts.filter.Level,
+		"path":       opts.filter.Path,
+		"request_id": opts.filter.RequestID,
+		"q":          opts.filter.Text,
+	} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+
+	if opts.follow {
+		return client.stream("/logs/tail?"+query.Encode(), func(event, data string) error {
+			switch event {
+			case "log":
+				var e logs.Entry
+				if err := json.Unmarshal([]byte(data), &e); err != nil {
+					return err
+				}
+				printLogEntry(e, opts.json)
+			case "error":
+				return i18n.Errorf("cli.logs.follow_failed", data)
+			}
+			return nil
+		})
+	}
+
+	query.Set("limit", strconv.Itoa(opts.filter.Limit))
+	var entries []logs.Entry
+	if err := client.do(http.MethodGet, "/logs?"+query.Encode(), nil, &entries); err != nil {
+		return err
+	}
+	printLogEntries(entries, opts.json)
+	return nil
+}
+
+// localLogs 直接读取配置中的日志文件
+func localLogs(cmd *cobra.Command, opts logsOptions) error {
+	if err := loadConfig(cmd, nil); err != nil {
+		return err
+	}
+	filename := config.GetLogConfig().Filename
+	f := opts.filter
+	var err error
+	now := time.Now()
+	if opts.since != "" {
+		if f.Since, err = logs.ParseTime(opts.since, now); err != nil {
+			return err
+		}
+	}
+	if opts.until != "" {
+		if f.Until, err = logs.ParseTime(opts.until, now); err != nil {
+			return err
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if opts.follow {
+		return logs.Follow(ctx, filename, f, func(e logs.Entry) error {
+			printLogEntry(e, opts.json)
+			return nil
+		})
+	}
+	entries, _, err := logs.Search(ctx, filename, f)
+	if err != nil {
+		return err
+	}
+	printLogEntries(entries, opts.json)
+	return nil
+}
+
+// printLogEntries 按时间顺序输出查询结果（接口按时间倒序返回）
+func printLogEntries(entries []logs.Entry, asJSON bool) {
+	for i := len(entries) - 1; i >= 0; i-- {
+		printLogEntry(entries[i], asJSON)
+	}
+}
+
+// printLogEntry 输出一条日志：时间 级别 [组件] 消息 key=value...
+func printLogEntry(e logs.Entry, asJSON bool) {
+	if asJSON {
+		data, _ := json.Marshal(e)
+		fmt.Println(string(data))
+		return
+	}
+	if e.Raw != "" {
+		fmt.Println(e.Raw)
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %-5s ", e.Time.Format(logs.TimeLayout), e.Level)
+	if e.Logger != "" {
+		fmt.Fprintf(&b, "[%s] ", e.Logger)
+	}
+	b.WriteString(e.Message)
+	keys := make([]string, 0, len(e.Fields))
+	for key := range e.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := e.Fields[key]
+		if value == "" || key == "stacktrace" {
+			continue
+		}
+		fmt.Fprintf(&b, " %s=%v", key, value)
+	}
+	fmt.Println(b.String())
+	if stack, ok := e.Fields["stacktrace"].(string); ok {
+		fmt.Println(stack)
+	}
+}
@@ -1,22 +1,145 @@
 package cmd
 
 import (
+	"HarborArk/config"
+	"HarborArk/internal/i18n"
+	"HarborArk/internal/service/audit"
+	"context"
 	"fmt"
 	"os"
+	"os/user"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var rootCmd = &cobra.Command{
 	Use:   "harborArk",
-	Short: "harborArk is a NAS application with Gin and Cobra",
-	Long: `harborArk is a NAS application that combines a Gin web server 
-with Cobra CLI functionality.`,
+	Short: "cli.root.short",
+	Long:  "cli.root.long",
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringP("config", "c", "", "cli.root.flags.config")
+	rootCmd.PersistentFlags().String("profile", "", "cli.root.flags.profile")
+	rootCmd.PersistentFlags().String("lang", "", "cli.root.flags.lang")
+}
+
+// loadConfig 根据全局 --config、--profile 标志加载配置，flags 为配置键到命令行标志的绑定
+func loadConfig(cmd *cobra.Command, flags map[string]*pflag.Flag) error {
+	file, profile := configFlags(cmd)
+	return config.Init(config.Options{File: file, Profile: profile, Flags: flags})
+}
+
+// configFlags 读取全局 --config、--profile 标志
+func configFlags(cmd *cobra.Command) (string, config.Runmode) {
+	file, _ := cmd.Flags().GetString("config")
+	profile, _ := cmd.Flags().GetString("profile")
+	return file, config.Runmode(profile)
+}
+
+// recordConfigChange 将命令行修改密钥、证书等配置的操作写入配置文件中设置的审计日志。
+// 审计日志未启用时忽略，写入失败只输出警告，不影响已完成的操作
+func recordConfigChange(cmd *cobra.Command, resource string, detail map[string]string) {
+	file, profile, err := config.FilePath(configFlags(cmd))
+	var cfg config.AuditConfig
+	if err == nil {
+		cfg, err = config.ReadAuditConfig(file, profile)
+	}
+	if err == nil && !cfg.Enabled {
+		return
+	}
+	var l *audit.Logger
+	if err == nil {
+		l, err = audit.Open(cfg.Filename)
+	}
+	if err == nil {
+		if detail == nil {
+			detail = make(map[string]string)
+		}
+		detail["command"] = cmd.CommandPath()
+		err = l.Record(context.Background(), audit.Entry{
+			Actor:    cliActor(),
+			Action:   audit.ActionConfigChange,
+			Resource: resource,
+			Detail:   detail,
+		})
+		l.Close()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "⚠️ ", i18n.T("cli.root.audit_failed", err))
+	}
+}
+
+// cliActor 返回执行命令的系统用户，作为审计记录的操作人
+func cliActor() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return "cli"
+}
+
+// cliLanguage 按 --lang 标志与 LC_ALL、LC_MESSAGES、LANG 环境变量确定命令行语言。
+// 命令与标志说明需在解析标志之前翻译，因此直接从参数中读取 --lang
+func cliLanguage(args []string) (string, error) {
+	value := ""
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if v, ok := strings.CutPrefix(arg, "--lang="); ok {
+			value = v
+			break
+		}
+		if arg == "--lang" && i+1 < len(args) {
+			value = args[i+1]
+			break
+		}
+	}
+	if value == "" {
+		if lang := i18n.FromEnv(); lang != "" {
+			return lang, nil
+		}
+		return i18n.Languages[0], nil
+	}
+	lang, ok := i18n.Parse(value)
+	if !ok {
+		return "", i18n.Errorf("cli.root.invalid_lang", value, strings.Join(i18n.Languages, ", "))
+	}
+	return lang, nil
+}
+
+// localize 将命令及其子命令的说明、标志说明中的消息键替换为当前语言的文本
+func localize(cmd *cobra.Command) {
+	cmd.Short = i18n.T(cmd.Short)
+	cmd.Long = i18n.T(cmd.Long)
+	cmd.InitDefaultHelpFlag()
+	translate := func(f *pflag.Flag) {
+		if f.Name == "help" {
+			f.Usage = i18n.T("cli.help_flag", cmd.Name())
+			return
+		}
+		f.Usage = i18n.T(f.Usage)
+	}
+	cmd.Flags().VisitAll(translate)
+	cmd.PersistentFlags().VisitAll(translate)
+	for _, sub := range cmd.Commands() {
+		localize(sub)
+	}
 }
 
 func Execute() {
+	lang, err := cliLanguage(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	i18n.SetLanguage(lang)
+	localize(rootCmd)
+
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, "Error executing command:", err)
+		fmt.Fprintln(os.Stderr, i18n.T("cli.root.error"), err)
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,302 @@
+package cmd
+
+import (
+	"HarborArk/config"
+	"HarborArk/internal/i18n"
+	"HarborArk/internal/secrets"
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	// 创建 secrets 主命令
+	secretsCmd := &cobra.Command{
+		Use:   "secrets",
+		Short: "cli.secrets.short",
+		Long:  "cli.secrets.long",
+	}
+
+	// 创建 set 子命令
+	setCmd := &cobra.Command{
+		Use:           "set <name> [value]",
+		Short:         "cli.secrets.set.short",
+		Long:          "cli.secrets.set.long",
+		Args:          cobra.RangeArgs(1, 2),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var value string
+			if len(args) == 2 {
+				value = args[1]
+			} else {
+				var err error
+				if value, err = readSecret(args[0]); err != nil {
+					return err
+				}
+			}
+			return setSecret(cmd, args[0], value)
+		},
+	}
+
+	// 创建 get 子命令
+	getCmd := &cobra.Command{
+		Use:           "get <name>",
+		Short:         "cli.secrets.get.short",
+		Long:          "cli.secrets.get.long",
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ks, _, err := openKeystore(cmd)
+			if err != nil {
+				return err
+			}
+			value, err := ks.Get(args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Println(value)
+			return nil
+		},
+	}
+
+	// 创建 list 子命令
+	listCmd := &cobra.Command{
+		Use:           "list",
+		Short:         "cli.secrets.list.short",
+		Long:          "cli.secrets.list.long",
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listSecrets(cmd)
+		},
+	}
+
+	// 创建 delete 子命令
+	deleteCmd := &cobra.Command{
+		Use:           "delete <name>",
+		Short:         "cli.secrets.delete.short",
+		Long:          "cli.secrets.delete.long",
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ks, _, err := openKeystore(cmd)
+			if err != nil {
+				return err
+			}
+			if err := ks.Delete(args[0]); err != nil {
+				return err
+			}
+			recordConfigChange(cmd, "secrets/"+args[0], map[string]string{"operation": "delete"})
+			fmt.Println("🗑️ ", i18n.T("cli.secrets.delete.done", args[0]))
+			return nil
+		},
+	}
+
+	// 创建 rotate 子命令
+	rotateCmd := &cobra.Command{
+		Use:           "rotate",
+		Short:         "cli.secrets.rotate.short",
+		Long:          "cli.secrets.rotate.long",
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			newKeyFile, _ := cmd.Flags().GetString("new-key-file")
+			return rotateSecrets(cmd, newKeyFile)
+		},
+	}
+
+	// 添加标志
+	rotateCmd.Flags().String("new-key-file", "", "cli.secrets.rotate.flags.new_key_file")
+
+	// 添加子命令
+	secretsCmd.AddCommand(setCmd)
+	secretsCmd.AddCommand(getCmd)
+	secretsCmd.AddCommand(listCmd)
+	secretsCmd.AddCommand(deleteCmd)
+	secretsCmd.AddCommand(rotateCmd)
+
+	// 添加到根命令
+	rootCmd.AddCommand(secretsCmd)
+}
+
+// secretsConfig 读取 --config/--profile 对应配置文件中的密钥库配置
+func secretsConfig(cmd *cobra.Command) (config.SecretsConfig, error) {
+	file, profile, err := config.FilePath(configFlags(cmd))
+	if err != nil {
+		return config.SecretsConfig{}, err
+	}
+	return config.ReadSecretsConfig(file, profile)
+}
+
+// openKeystore 使用主密钥打开已有的密钥库
+func openKeystore(cmd *cobra.Command) (*secrets.Keystore, config.SecretsConfig, error) {
+	cfg, err := secretsConfig(cmd)
+	if err != nil {
+		return nil, cfg, err
+	}
+	key, _, err := secrets.MasterKey(cfg.KeyFile)
+	if err != nil {
+		return nil, cfg, err
+	}
+	if _, err := os.Stat(cfg.Keystore); errors.Is(err, os.ErrNotExist) {
+		return nil, cfg, i18n.Errorf("cli.secrets.keystore_missing", cfg.Keystore)
+	}
+	ks, err := secrets.Open(cfg.Keystore, key)
+	return ks, cfg, err
+}
+
+// setSecret 保存密钥，首次使用时创建主密钥文件与密钥库
+func setSecret(cmd *cobra.Command, name, value string) error {
+	cfg, err := secretsConfig(cmd)
+	if err != nil {
+		return err
+	}
+	key, _, err := secrets.MasterKey(cfg.KeyFile)
+	if err != nil {
+		keyFile := masterKeyFile(cfg)
+		if _, statErr := os.Stat(keyFile); keyFile == "" || !errors.Is(statErr, os.ErrNotExist) {
+			return err
+		}
+		if _, statErr := os.Stat(cfg.Keystore); statErr == nil {
+			return i18n.Errorf("cli.secrets.key_missing", keyFile, cfg.Keystore)
+		}
+		if key, err = createMasterKey(keyFile); err != nil {
+			return err
+		}
+		fmt.Println("🔑", i18n.T("cli.secrets.key_generated", keyFile))
+	}
+
+	ks, err := secrets.OpenOrCreate(cfg.Keystore, key)
+	if err != nil {
+		return err
+	}
+	if err := ks.Set(name, value); err != nil {
+		return err
+	}
+	recordConfigChange(cmd, "secrets/"+name, map[string]string{"operation": "set"})
+	fmt.Println("✅", i18n.T("cli.secrets.set.done", name))
+	fmt.Println("  ", i18n.T("cli.secrets.set.reference", "${secret:"+name+"}"))
+	return nil
+}
+
+// listSecrets 输出密钥名称与更新时间
+func listSecrets(cmd *cobra.Command) error {
+	ks, cfg, err := openKeystore(cmd)
+	if err != nil {
+		return err
+	}
+	entries := ks.List()
+	if len(entries) == 0 {
+		fmt.Println(i18n.T("cli.secrets.list.empty", cfg.Keystore))
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tUPDATED")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%s\n", e.Name, e.UpdatedAt.Format(time.RFC3339))
+	}
+	return w.Flush()
+}
+
+// rotateSecrets 生成新的主密钥并重新加密密钥库。新密钥先写入临时文件，
+// 密钥库重新加密成功后再替换原文件，中途失败时旧密钥仍可用
+func rotateSecrets(cmd *cobra.Command, newKeyFile string) error {
+	ks, cfg, err := openKeystore(cmd)
+	if err != nil {
+		return err
+	}
+	target := newKeyFile
+	if target == "" {
+		if target = masterKeyFile(cfg); target == "" {
+			return i18n.Errorf("cli.secrets.rotate.env_key", secrets.EnvMasterKey)
+		}
+	}
+
+	pending := target + ".new"
+	key, err := createMasterKey(pending)
+	if err != nil {
+		return err
+	}
+	if err := ks.Rotate(key); err != nil {
+		os.Remove(pending)
+		return i18n.Errorf("cli.secrets.rotate.reencrypt_failed", err)
+	}
+	if err := os.Rename(pending, target); err != nil {
+		return i18n.Errorf("cli.secrets.rotate.replace_failed", pending, err)
+	}
+	recordConfigChange(cmd, "secrets", map[string]string{
+		"operation": "rotate",
+		"secrets":   strconv.Itoa(len(ks.List())),
+	})
+
+	fmt.Println("🔄", i18n.T("cli.secrets.rotate.done", len(ks.List())))
+	fmt.Println("  ", i18n.T("cli.secrets.rotate.new_key", target))
+	if os.Getenv(secrets.EnvMasterKey) != "" {
+		fmt.Println("  ", i18n.T("cli.secrets.rotate.update_env", secrets.EnvMasterKey))
+	} else if newKeyFile != "" {
+		fmt.Println("  ", i18n.T("cli.secrets.rotate.update_file", secrets.EnvMasterKeyFile))
+	}
+	return nil
+}
+
+// masterKeyFile 返回主密钥文件路径，主密钥直接来自环境变量时返回空
+func masterKeyFile(cfg config.SecretsConfig) string {
+	if os.Getenv(secrets.EnvMasterKey) != "" {
+		return ""
+	}
+	if file := os.Getenv(secrets.EnvMasterKeyFile); file != "" {
+		return file
+	}
+	return cfg.KeyFile
+}
+
+// createMasterKey 生成主密钥并写入仅所有者可读的文件
+func createMasterKey(file string) ([]byte, error) {
+	key, err := secrets.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return nil, i18n.Errorf("cli.secrets.key_mkdir_failed", err)
+	}
+	if err := os.WriteFile(file, []byte(key+"\n"), 0600); err != nil {
+		return nil, i18n.Errorf("cli.secrets.key_write_failed", err)
+	}
+	return []byte(key), nil
+}
+
+// readSecret 从标准输入读取密钥，终端输入时只读取一行
+func readSecret(name string) (string, error) {
+	var data []byte
+	var err error
+	if info, statErr := os.Stdin.Stat(); statErr == nil && info.Mode()&os.ModeCharDevice != 0 {
+		fmt.Fprint(os.Stderr, i18n.T("cli.secrets.set.prompt", name))
+		var line string
+		line, err = bufio.NewReader(os.Stdin).ReadString('\n')
+		data = []byte(line)
+	} else {
+		data, err = io.ReadAll(os.Stdin)
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", i18n.Errorf("cli.secrets.set.read_failed", err)
+	}
+	value := strings.TrimRight(string(data), "\r\n")
+	if value == "" {
+		return "", errors.New(i18n.T("cli.secrets.set.empty"))
+	}
+	return value, nil
+}
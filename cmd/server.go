@@ -2,29 +2,48 @@ package cmd
 
 import (
 	"HarborArk/config"
+	"HarborArk/internal/certs"
 	"HarborArk/internal/controller"
+	"HarborArk/internal/health"
+	"HarborArk/internal/i18n"
+	"HarborArk/internal/logging"
+	"HarborArk/internal/server"
+	"HarborArk/internal/service/audit"
+	"HarborArk/internal/service/logs"
+	"HarborArk/internal/service/webhook"
+	"HarborArk/internal/tracing"
+	"HarborArk/internal/validation"
 	"HarborArk/router"
 	"HarborArk/router/middleware"
+	"context"
 	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"go.uber.org/zap"
+	"golang.org/x/crypto/acme"
 
 	_ "HarborArk/cmd/docs"
 )
 
 var serverCmd = &cobra.Command{
 	Use:   "server",
-	Short: "启动 HarborArk 服务器",
-	Long:  `启动 HarborArk API 服务器`,
+	Short: "cli.server.short",
+	Long:  "cli.server.long",
 	Run: func(cmd *cobra.Command, args []string) {
-		startServer()
+		startServer(cmd)
 	},
 }
 
 func init() {
-	serverCmd.Flags().StringP("port", "p", "8080", "服务器运行端口")
+	serverCmd.Flags().StringP("port", "p", "8080", "cli.server.flags.port")
 	rootCmd.AddCommand(serverCmd)
 }
 
@@ -42,10 +61,14 @@ func init() {
 
 // @host localhost:8080
 // @BasePath /api/v1
-func startServer() {
-	// 初始化配置
-	if err := config.Init(); err != nil {
-		panic(fmt.Errorf("初始化配置失败: %v", err))
+func startServer(cmd *cobra.Command) {
+	// 初始化配置，--port 优先于环境变量与配置文件
+	if err := loadConfig(cmd, map[string]*pflag.Flag{
+		"server.port": cmd.Flags().Lookup("port"),
+	}); err != nil {
+		// 配置错误由使用者修正，直接列出全部问题而不是输出调用栈
+		fmt.Fprintln(os.Stderr, i18n.T("cli.server.config_failed", err))
+		os.Exit(1)
 	}
 
 	// 获取配置
@@ -55,9 +78,34 @@ func startServer() {
 
 	// 初始化日志系统
 	if err := middleware.Init(logConfig, serverConfig.Mode); err != nil {
-		panic(fmt.Errorf("初始化日志失败: %v", err))
+		panic(i18n.Errorf("cli.server.logger_failed", err))
 	}
 
+	// 初始化链路追踪
+	tracingConfig := config.GetTracingConfig()
+	shutdownTracing, err := tracing.Init(tracingConfig)
+	if err != nil {
+		panic(i18n.Errorf("cli.server.tracing_failed", err))
+	}
+
+	// 初始化审计日志
+	if auditConfig := config.GetAuditConfig(); auditConfig.Enabled {
+		if err := audit.Init(auditConfig.Filename); err != nil {
+			panic(i18n.Errorf("cli.server.audit_failed", err))
+		}
+	}
+
+	// 初始化 Webhook 投递
+	if err := webhook.Init(config.GetWebhookConfig()); err != nil {
+		panic(i18n.Errorf("cli.server.webhook_failed", err))
+	}
+
+	// 注册健康检查项
+	registerHealthChecks()
+
+	// 监听配置文件，热加载日志级别等配置
+	watchConfig()
+
 	// 自动更新 Swagger 文档
 	if swaggerConfig.AutoUpdate && swaggerConfig.Enabled {
 		AutoUpdateSwaggerDocs()
@@ -69,34 +117,54 @@ func startServer() {
 		gin.ForceConsoleColor()
 	}
 
+	// 请求参数校验错误使用 json 字段名与中英文提示
+	validation.Setup()
+
 	// 创建路由
 	r := gin.New()
 
+	// 只信任配置的反向代理转发的客户端 IP，限流、审计与访问日志均依赖 ClientIP
+	if err := r.SetTrustedProxies(serverConfig.TrustedProxies); err != nil {
+		zap.L().Fatal("可信代理配置无效", zap.Error(err))
+	}
+	r.RemoteIPHeaders = serverConfig.RemoteIPHeaders
+
 	// 添加中间件
-	r.Use(middleware.GinLogger(), middleware.GinRecovery(true))
+	r.Use(otelgin.Middleware(tracingConfig.ServiceName), middleware.RequestID(), middleware.Locale())
+	if accessLogConfig := config.GetAccessLogConfig(); accessLogConfig.Enabled {
+		r.Use(middleware.AccessLog(accessLogConfig))
+	}
+	r.Use(middleware.GinLogger(), middleware.GinMetrics(), middleware.GinRecovery(true))
+	securityConfig := config.GetSecurityConfig()
+	r.Use(middleware.SecurityHeaders(securityConfig.Headers), middleware.CORS(securityConfig.CORS))
+	if serverConfig.TLS.Enabled {
+		r.Use(middleware.ClientCertAuth(serverConfig.TLS))
+	}
+	r.Use(middleware.DebugLogging())
 
 	// 设置 Swagger 文档
 	router.SetupSwagger(r)
 
+	// 设置 Prometheus 指标
+	router.SetupMetrics(r)
+
+	// 未匹配的路由返回统一的错误响应
+	router.SetupErrors(r)
+
 	// 基础路由
 	r.GET("/", func(c *gin.Context) {
 		c.JSON(200, gin.H{
-			"message": "欢迎使用 HarborArk API!",
+			"message": i18n.Ctx(c.Request.Context()).T("api.welcome"),
 			"version": "1.0.0",
 			"docs":    "/swagger/index.html",
 		})
 	})
 
 	// 健康检查
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status": "ok",
-			"time":   "2025-01-21T11:00:00Z",
-		})
-	})
+	router.SetupHealth(r)
 
 	// API 路由组
-	v1 := r.Group("/api/v1")
+	v1 := r.Group("/api/v1", middleware.RateLimit(config.GetRateLimitConfig()), middleware.CSRF(securityConfig.CSRF))
 	{
 		v1.GET("/ping", func(c *gin.Context) {
 			c.JSON(200, gin.H{
@@ -111,17 +179,263 @@ func startServer() {
 			users.GET("/:id", controller.GetUser)
 			users.POST("", controller.CreateUser)
 		}
+
+		// 管理与审计接口只对 admin 角色的客户端证书开放，未配置管理员认证时一律返回 403
+		requireAdmin := middleware.RequireAdmin()
+		if !serverConfig.TLS.AdminAuth() {
+			requireAdmin = middleware.AdminNotConfigured()
+			zap.L().Warn("未配置管理员认证，管理与审计接口已禁用",
+				zap.String("hint", "启用 server.tls 与 clientAuth，并在 clientUsers 中将证书映射为 role: admin"))
+		}
+
+		// 审计日志路由
+		auditLogs := v1.Group("/audit", requireAdmin)
+		{
+			auditLogs.GET("", controller.GetAuditLogs)
+			auditLogs.GET("/export", controller.ExportAuditLogs)
+			auditLogs.GET("/verify", controller.VerifyAuditLog)
+		}
+
+		// 管理路由
+		admin := v1.Group("/admin", requireAdmin)
+		{
+			webhooks := admin.Group("/webhooks")
+			{
+				webhooks.GET("", controller.GetWebhooks)
+				webhooks.POST("", controller.CreateWebhook)
+				webhooks.DELETE("/:id", controller.DeleteWebhook)
+				webhooks.POST("/:id/ping", controller.PingWebhook)
+				webhooks.GET("/:id/deliveries", controller.GetWebhookDeliveries)
+				webhooks.POST("/:id/deliveries/:deliveryId/redeliver", controller.RedeliverWebhook)
+			}
+
+			logging := admin.Group("/logging")
+			{
+				logging.GET("", controller.GetLogging)
+				logging.PUT("/level", controller.SetLogLevel)
+				logging.PUT("/loggers/:name", controller.SetLoggerLevel)
+				logging.DELETE("/loggers/:name", controller.ResetLoggerLevel)
+				logging.POST("/debug", controller.CreateDebugRule)
+				logging.DELETE("/debug/:id", controller.DeleteDebugRule)
+			}
+
+			appLogs := admin.Group("/logs")
+			{
+				appLogs.GET("", controller.GetLogs)
+				appLogs.GET("/tail", controller.TailLogs)
+			}
+
+			lockouts := admin.Group("/lockouts")
+			{
+				lockouts.GET("", controller.GetLockouts)
+				lockouts.DELETE("", controller.DeleteLockout)
+			}
+		}
 	}
 
 	// 启动服务器
 	port := ":" + serverConfig.Port
+	srv := server.New(serverConfig.ShutdownTimeout)
+	httpServer := &http.Server{
+		Handler:           r,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	// 实时日志等长连接不会自行结束，关闭时主动断开以免拖慢优雅关闭
+	httpServer.RegisterOnShutdown(logs.StopFollowing)
+	scheme := "http"
+	var source certs.Source
+	if serverConfig.TLS.Enabled {
+		var err error
+		if source, err = setupTLS(httpServer, serverConfig); err != nil {
+			zap.L().Fatal("初始化 TLS 失败", zap.Error(err))
+		}
+		srv.OnShutdown(func(context.Context) error { return source.Close() })
+		health.Register("tls_certificate", health.Readiness,
+			health.CertExpiryCheck(source.Expiry, config.GetHealthConfig().MinCertValidity))
+		scheme = "https"
+	}
+	if err := srv.Handle(port, httpServer); err != nil {
+		zap.L().Fatal("服务器启动失败", zap.Error(err))
+	}
+
+	// HTTP 监听：重定向到 HTTPS，启用 ACME 时同时响应 HTTP-01 验证
+	acmeSource, isACME := source.(*certs.ACME)
+	if serverConfig.TLS.Enabled && (serverConfig.TLS.RedirectHTTP || isACME) {
+		var handler http.Handler
+		if serverConfig.TLS.RedirectHTTP {
+			handler = certs.RedirectHandler(serverConfig.Port)
+		}
+		if isACME {
+			handler = acmeSource.HTTPHandler(handler)
+		}
+		if err := srv.Handle(":"+serverConfig.TLS.HTTPPort, &http.Server{
+			Handler:           handler,
+			ReadHeaderTimeout: 10 * time.Second,
+		}); err != nil {
+			zap.L().Fatal("HTTP 监听启动失败", zap.Error(err))
+		}
+	}
+	if isACME {
+		acmeSource.Prefetch()
+	}
+
+	// 关闭顺序：停止后台任务 -> 刷新追踪数据 -> 关闭审计日志 -> 刷新日志
+	if d := webhook.Default(); d != nil {
+		srv.OnHandoff(d.Stop)
+		srv.OnShutdown(d.Stop)
+	}
+	srv.OnShutdown(shutdownTracing)
+	srv.OnShutdown(func(context.Context) error {
+		if l := audit.Default(); l != nil {
+			return l.Close()
+		}
+		return nil
+	})
+
 	zap.L().Info("服务器启动中...",
 		zap.String("port", serverConfig.Port),
 		zap.String("mode", serverConfig.Mode),
-		zap.String("docs", "http://localhost:"+serverConfig.Port+"/swagger/index.html"),
+		zap.Bool("tls", serverConfig.TLS.Enabled),
+		zap.String("config", config.File()),
+		zap.String("profile", string(config.Profile())),
+		zap.String("docs", scheme+"://localhost:"+serverConfig.Port+"/swagger/index.html"),
 	)
 
-	if err := r.Run(port); err != nil {
-		zap.L().Fatal("服务器启动失败", zap.Error(err))
+	if err := srv.Run(); err != nil {
+		zap.L().Error("服务器关闭时出现错误", zap.Error(err))
+	}
+	zap.L().Info("服务器已退出")
+	middleware.Sync() // nolint: errcheck
+}
+
+// watchConfig 监听配置文件，将支持热加载的配置应用到运行中的服务
+func watchConfig() {
+	config.Subscribe(func(prev, next *config.AppConfig) {
+		// 只记录修改的配置项，不记录值，避免敏感信息进入审计日志
+		detail := map[string]string{
+			"file": config.File(),
+			"keys": strings.Join(changedKeys(prev, next), ","),
+		}
+		if pending := config.Pending(); pending != nil {
+			detail["pending"] = strings.Join(changedKeys(next, pending), ",")
+		}
+		audit.Record(context.Background(), audit.Entry{
+			Actor:    "system",
+			Action:   audit.ActionConfigChange,
+			Resource: "config",
+			Detail:   detail,
+		})
+
+		if prev.Logger.Level != next.Logger.Level {
+			if err := logging.SetLevel(next.Logger.Level); err != nil {
+				zap.L().Error("修改日志级别失败", zap.Error(err))
+			} else {
+				zap.L().Info("日志级别已修改", zap.String("from", prev.Logger.Level), zap.String("to", next.Logger.Level))
+			}
+		}
+		applyLoggerLevels(prev.Logger.Levels, next.Logger.Levels)
+		if !reflect.DeepEqual(prev.Logger.Redact, next.Logger.Redact) {
+			middleware.SetRedaction(next.Logger.Redact)
+			zap.L().Info("日志脱敏规则已修改")
+		}
+		if !reflect.DeepEqual(prev.AccessLog, next.AccessLog) {
+			middleware.SetAccessLogRules(next.AccessLog)
+			zap.L().Info("访问日志规则已修改", zap.String("format", next.AccessLog.Format))
+		}
+
+		if !reflect.DeepEqual(prev.RateLimit, next.RateLimit) {
+			middleware.SetRateLimits(next.RateLimit)
+			zap.L().Info("限流规则已修改", zap.Bool("enabled", next.RateLimit.Enabled))
+		}
+
+		if !reflect.DeepEqual(prev.Security, next.Security) {
+			middleware.SetSecurity(next.Security)
+			zap.L().Info("安全配置已修改", zap.Strings("cors_origins", next.Security.CORS.AllowOrigins))
+		}
+
+		if prev.Swagger.Enabled != next.Swagger.Enabled {
+			zap.L().Info("Swagger 文档开关已修改", zap.Bool("enabled", next.Swagger.Enabled))
+		}
+	})
+	config.Watch()
+}
+
+// changedKeys 返回两份配置中值不同的配置项
+func changedKeys(prev, next *config.AppConfig) []string {
+	var keys []string
+	nextSettings := config.Flatten(next, nil)
+	for i, setting := range config.Flatten(prev, nil) {
+		if setting.Value != nextSettings[i].Value {
+			keys = append(keys, setting.Key)
+		}
+	}
+	return keys
+}
+
+// applyLoggerLevels 应用配置文件中修改的组件日志级别，已删除的组件恢复使用全局级别
+func applyLoggerLevels(prev, next map[string]string) {
+	for name := range prev {
+		if _, ok := next[name]; !ok {
+			logging.ResetLoggerLevel(name)
+			zap.L().Info("组件日志级别已恢复为全局级别", zap.String("logger", name))
+		}
+	}
+	for name, text := range next {
+		if prev[name] == text {
+			continue
+		}
+		if err := logging.SetLoggerLevel(name, text); err != nil {
+			zap.L().Error("修改组件日志级别失败", zap.String("logger", name), zap.Error(err))
+			continue
+		}
+		zap.L().Info("组件日志级别已修改", zap.String("logger", name), zap.String("level", text))
+	}
+}
+
+// registerHealthChecks 注册存储卷与后台任务的健康检查项
+func registerHealthChecks() {
+	healthConfig := config.GetHealthConfig()
+	health.Default().SetTimeout(healthConfig.Timeout)
+
+	for _, vol := range config.GetStorageConfig().Volumes {
+		health.Register("volume:"+vol.Name, health.Readiness, health.VolumeCheck(vol))
+		health.Register("free_space:"+vol.Name, health.Readiness, health.FreeSpaceCheck(vol, healthConfig.MinFreePercent))
+	}
+
+	if d := webhook.Default(); d != nil {
+		health.Register("webhook_workers", health.Liveness|health.Readiness, health.CheckFunc(d.Alive))
+	}
+}
+
+// setupTLS 根据配置选择 ACME 或证书文件作为证书来源，为 httpServer 配置 TLS
+func setupTLS(httpServer *http.Server, serverConfig config.ServerConfig) (certs.Source, error) {
+	var source certs.Source
+	if serverConfig.TLS.ACME.Enabled {
+		acmeSource, err := certs.NewACME(serverConfig.TLS.ACME)
+		if err != nil {
+			return nil, err
+		}
+		source = acmeSource
+	} else {
+		reloader, err := certs.NewReloader(serverConfig.TLS.CertFile, serverConfig.TLS.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if err := reloader.Watch(); err != nil {
+			return nil, err
+		}
+		source = reloader
+	}
+
+	tlsConfig, err := certs.ServerTLSConfig(serverConfig.TLS, source.GetCertificate)
+	if err != nil {
+		source.Close()
+		return nil, err
+	}
+	if serverConfig.TLS.ACME.Enabled {
+		// TLS-ALPN-01 验证
+		tlsConfig.NextProtos = append(tlsConfig.NextProtos, acme.ALPNProto)
 	}
+	httpServer.TLSConfig = tlsConfig
+	return source, nil
 }
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"HarborArk/internal/i18n"
 	"fmt"
 
 	"github.com/spf13/cobra"
@@ -8,9 +9,9 @@ import (
 
 var versionCmd = &cobra.Command{
 	Use:   "version",
-	Short: "Print the version number",
+	Short: "cli.version.short",
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("Version 0.1.0")
+		fmt.Println(i18n.T("cli.version.output", "0.1.0"))
 	},
 }
 
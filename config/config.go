@@ -1,11 +1,15 @@
 package config
 
 import (
-	"fmt"
+	"HarborArk/internal/i18n"
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
-	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
 )
 
 type (
@@ -13,36 +17,196 @@ type (
 	Runmode string
 )
 
+// 运行环境，决定默认加载的配置文件 config/settings-<profile>.yaml
 const (
 	RunmodeDev  Runmode = "dev"
 	RunmodeProd Runmode = "prod"
 	RunmodeTest Runmode = "test"
 )
 
-func Setup(configFilePath, configFileType string) {
-	viper.SetConfigFile(configFilePath)
-	viper.SetConfigType(configFileType)
+// WriteConfig 写入配置文件
+func WriteConfig(filename string) {
+	viper.WriteConfigAs(filename)
+}
 
-	if err := viper.ReadInConfig(); err != nil {
-		panic(fmt.Sprintf("读取配置文件失败，请检查 %s 配置文件是否存在: %v", configFilePath, err))
+// UpdateFile 修改 YAML 配置文件中的指定项，键使用点号分隔（如 server.tls.enabled），
+// 与 WriteConfig 不同，会保留文件中原有的注释、空行与顺序。先写临时文件再重命名，写入中断不会损坏配置文件
+func UpdateFile(filename string, values map[string]interface{}) error {
+	// 配置文件可能是指向其他位置的符号链接，重命名时替换链接指向的文件
+	if target, err := filepath.EvalSymlinks(filename); err == nil {
+		filename = target
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return i18n.NewError("errors.config.read_failed", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return i18n.NewError("errors.config.parse_failed", err)
+	}
+	if len(doc.Content) == 0 {
+		doc.Kind = yaml.DocumentNode
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode}}
 	}
-	viper.AutomaticEnv()                                   // 自动读取环境变量
-	viper.SetEnvPrefix("harborark")                        // 设置环境变量前缀
-	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_")) // 替换点
 
-	watchConfigChanges()
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
 
-	fmt.Printf("\nConfiguration file loaded successfully: %s\n", configFilePath)
+	// 只修改独占一行的已有标量时逐行替换原文，否则整体重新编码（会丢失空行）
+	lines := strings.Split(string(data), "\n")
+	inPlace := true
+	for _, key := range keys {
+		var value yaml.Node
+		if err := value.Encode(values[key]); err != nil {
+			return i18n.NewError("errors.config.encode_failed", key, err)
+		}
+		old, parent, err := setNode(doc.Content[0], strings.Split(key, "."), &value)
+		if err != nil {
+			return i18n.NewError("errors.config.set_failed", key, err)
+		}
+		if !inPlace {
+			continue
+		}
+		line, ok := replaceScalar(lines, parent, old, &value)
+		if !ok {
+			inPlace = false
+			continue
+		}
+		lines[old.Line-1] = line
+	}
+
+	var out []byte
+	if inPlace {
+		out = []byte(strings.Join(lines, "\n"))
+	} else {
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(&doc); err != nil {
+			return err
+		}
+		enc.Close()
+		out = buf.Bytes()
+	}
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+	tmp := filename + ".tmp"
+	if err := os.WriteFile(tmp, out, info.Mode().Perm()); err != nil {
+		return i18n.NewError("errors.config.write_failed", err)
+	}
+	if err := os.Rename(tmp, filename); err != nil {
+		os.Remove(tmp)
+		return i18n.NewError("errors.config.write_failed", err)
+	}
+	return nil
 }
 
-// WriteConfig 写入配置文件
-func WriteConfig(filename string) {
-	viper.WriteConfigAs(filename)
+// replaceScalar 返回将原标量替换为新值后的行。只有原值是块映射中的单行标量（普通或引号风格）、
+// 其后只有空白或注释，且新值也能写成单行标量时才能逐行替换，否则返回 false。
+// 流式映射、多行标量、锚点与标签等情况替换后可能破坏原文的结构
+func replaceScalar(lines []string, parent, old, value *yaml.Node) (string, bool) {
+	if old == nil || parent.Style&yaml.FlowStyle != 0 || old.Kind != yaml.ScalarNode || value.Kind != yaml.ScalarNode ||
+		old.Style&^(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) != 0 || old.Anchor != "" ||
+		old.Line < 1 || old.Line > len(lines) {
+		return "", false
+	}
+	// yaml.v3 的列号按字符计数
+	line := []rune(lines[old.Line-1])
+	if old.Column < 1 || old.Column > len(line) {
+		return "", false
+	}
+	end := scalarEnd(line, old.Column-1, old.Style)
+	if end < 0 {
+		return "", false
+	}
+	// 原文解析后应与节点的值相同，否则原值跨行或带有标签
+	var parsed string
+	if err := yaml.Unmarshal([]byte(string(line[old.Column-1:end])), &parsed); err != nil || parsed != old.Value {
+		return "", false
+	}
+	if rest := strings.TrimSpace(string(line[end:])); rest != "" && !strings.HasPrefix(rest, "#") {
+		return "", false
+	}
+
+	// 行尾注释保留在原文中，编码时去掉
+	bare := *value
+	bare.LineComment = ""
+	text, err := yaml.Marshal(&bare)
+	if err != nil {
+		return "", false
+	}
+	encoded := strings.TrimSpace(string(text))
+	if strings.Contains(encoded, "\n") {
+		return "", false
+	}
+	return string(line[:old.Column-1]) + encoded + string(line[end:]), true
 }
 
-func watchConfigChanges() {
-	viper.WatchConfig()
-	viper.OnConfigChange(func(e fsnotify.Event) {
-		fmt.Printf("Config file changed: %s\n", e.Name)
-	})
+// scalarEnd 返回从 start 开始的标量在行内的结束位置，引号未在本行闭合时返回 -1
+func scalarEnd(line []rune, start int, style yaml.Style) int {
+	switch style {
+	case yaml.DoubleQuotedStyle:
+		for i := start + 1; i < len(line); i++ {
+			switch line[i] {
+			case '\\':
+				i++
+			case '"':
+				return i + 1
+			}
+		}
+		return -1
+	case yaml.SingleQuotedStyle:
+		for i := start + 1; i < len(line); i++ {
+			if line[i] != '\'' {
+				continue
+			}
+			if i+1 < len(line) && line[i+1] == '\'' {
+				i++
+				continue
+			}
+			return i + 1
+		}
+		return -1
+	}
+	// 普通标量在 " #" 注释前结束
+	for i := start; i < len(line); i++ {
+		if line[i] == '#' && i > start && (line[i-1] == ' ' || line[i-1] == '\t') {
+			return len([]rune(strings.TrimRight(string(line[:i]), " \t")))
+		}
+	}
+	return len([]rune(strings.TrimRight(string(line), " \t")))
+}
+
+// setNode 在映射节点中按路径设置值，缺失的中间层级会自动创建，返回被替换的原节点及其所在的映射
+func setNode(node *yaml.Node, path []string, value *yaml.Node) (old, parent *yaml.Node, err error) {
+	if node.Kind != yaml.MappingNode {
+		return nil, nil, i18n.NewError("errors.config.not_mapping", node.Value)
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			old := node.Content[i+1]
+			value.LineComment = old.LineComment
+			node.Content[i+1] = value
+			return old, node, nil
+		}
+		return setNode(node.Content[i+1], path[1:], value)
+	}
+
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: path[0]}
+	if len(path) == 1 {
+		node.Content = append(node.Content, key, value)
+		return nil, node, nil
+	}
+	child := &yaml.Node{Kind: yaml.MappingNode}
+	node.Content = append(node.Content, key, child)
+	return setNode(child, path[1:], value)
 }
@@ -0,0 +1,137 @@
+package config
+
+import "time"
+
+// Default 返回内置默认配置，优先级最低，未在配置文件、环境变量和命令行中出现的项使用此处的值
+func Default() *AppConfig {
+	return &AppConfig{
+		Server: ServerConfig{
+			Port:            "8080",
+			Mode:            "debug",
+			ShutdownTimeout: 30 * time.Second,
+			RemoteIPHeaders: []string{"X-Forwarded-For", "X-Real-IP"},
+			TLS: TLSConfig{
+				MinVersion: "1.2",
+				HTTPPort:   "8081",
+				ClientAuth: "none",
+				ACME: ACMEConfig{
+					DirectoryURL: "https://acme-v02.api.letsencrypt.org/directory",
+					CacheDir:     "data/acme",
+					RenewBefore:  30 * 24 * time.Hour,
+				},
+			},
+		},
+		Logger: LogConfig{
+			Level:      "info",
+			Encoding:   "json",
+			Filename:   "logs/app.log",
+			MaxSize:    100,
+			MaxAge:     7,
+			MaxBackups: 10,
+			Redact: RedactConfig{
+				Headers: []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key", "X-Auth-Token", "X-CSRF-Token"},
+				Query:   []string{"password", "passwd", "pwd", "token", "access_token", "refresh_token", "api_key", "secret", "signature", "sig", "code"},
+				Fields:  []string{"password", "passwd", "old_password", "new_password", "token", "access_token", "refresh_token", "api_key", "secret", "client_secret", "authorization", "cookie", "private_key"},
+				Mask:    "******",
+			},
+		},
+		Swagger: SwaggerConfig{
+			Title:       "HarborArk API",
+			Description: "HarborArk API Documentation",
+			Version:     "1.0.0",
+			Host:        "localhost:8080",
+			BasePath:    "/api/v1",
+			Enabled:     true,
+			AutoUpdate:  false,
+			OutputDir:   "cmd/docs",
+			MainApiFile: "cmd/server.go",
+			Schemes:     []string{"http", "https"},
+		},
+		Webhook: WebhookConfig{
+			Enabled:        true,
+			StoreFile:      "data/webhooks.json",
+			Workers:        2,
+			MaxAttempts:    8,
+			InitialBackoff: 5 * time.Second,
+			MaxBackoff:     30 * time.Minute,
+			Timeout:        10 * time.Second,
+			HistoryLimit:   200,
+		},
+		Audit: AuditConfig{
+			Enabled:  true,
+			Filename: "data/audit.log",
+		},
+		AccessLog: AccessLogConfig{
+			Enabled:    false,
+			Format:     "combined",
+			Filename:   "logs/access.log",
+			MaxSize:    100,
+			MaxAge:     7,
+			MaxBackups: 10,
+			Skip:       []string{"/health", "/livez", "/readyz", "/metrics"},
+		},
+		Metrics: MetricsConfig{
+			Enabled: true,
+			Path:    "/metrics",
+		},
+		Storage: StorageConfig{
+			Volumes: []VolumeConfig{
+				{Name: "data", Path: "data"},
+			},
+		},
+		Tracing: TracingConfig{
+			Enabled:     false,
+			ServiceName: "harborark",
+			Endpoint:    "http://localhost:4318/v1/traces",
+			SampleRatio: 1,
+		},
+		Health: HealthConfig{
+			Timeout:         3 * time.Second,
+			MinFreePercent:  5,
+			MinCertValidity: 7 * 24 * time.Hour,
+		},
+		Secrets: SecretsConfig{
+			Keystore: "config/secrets.keystore",
+			KeyFile:  "config/master.key",
+		},
+		RateLimit: RateLimitConfig{
+			Enabled: true,
+			IP:      RateLimitRule{Limit: 300, Period: time.Minute, Burst: 60},
+			User:    RateLimitRule{Limit: 600, Period: time.Minute, Burst: 120},
+			Lockout: LockoutConfig{
+				Enabled:     true,
+				MaxFailures: 5,
+				Window:      15 * time.Minute,
+				Duration:    time.Minute,
+				MaxDuration: time.Hour,
+			},
+		},
+		Security: SecurityConfig{
+			CORS: CORSConfig{
+				Enabled:      false,
+				AllowOrigins: []string{},
+				AllowMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+				AllowHeaders: []string{"Content-Type", "Authorization", "Accept-Language", "X-Request-ID", "X-CSRF-Token"},
+				ExposeHeaders: []string{"X-Request-ID", "X-CSRF-Token", "Content-Language", "Content-Disposition", "Retry-After",
+					"RateLimit-Policy", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset"},
+				MaxAge: 12 * time.Hour,
+			},
+			Headers: SecurityHeadersConfig{
+				Enabled:               true,
+				ContentSecurityPolicy: "default-src 'none'; frame-ancestors 'none'; base-uri 'none'; form-action 'none'",
+				HSTSMaxAge:            180 * 24 * time.Hour,
+				HSTSIncludeSubdomains: true,
+				FrameOptions:          "DENY",
+				ReferrerPolicy:        "no-referrer",
+				ContentTypeNosniff:    true,
+			},
+			CSRF: CSRFConfig{
+				Enabled:    true,
+				CookieName: "csrf_token",
+				HeaderName: "X-CSRF-Token",
+				SameSite:   "lax",
+				MaxAge:     12 * time.Hour,
+			},
+		},
+	}
+}
@@ -0,0 +1,249 @@
+package config
+
+import (
+	"HarborArk/internal/i18n"
+	"bytes"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// secretPattern 匹配需要隐藏的配置项名称
+var secretPattern = regexp.MustCompile(`(?i)(password|passwd|secret|token|apikey|accesskey|privatekey|masterkey|authorization)$`)
+
+// IsSecret 判断配置项是否为敏感信息，展示时需隐藏其值
+func IsSecret(key string) bool {
+	if i := strings.LastIndex(key, "."); i >= 0 {
+		key = key[i+1:]
+	}
+	return secretPattern.MatchString(key)
+}
+
+// redacted 敏感配置项展示时使用的占位符
+const redacted = "******"
+
+// RenderOptions 控制 Render 的输出
+type RenderOptions struct {
+	// Comments 为每项添加说明注释
+	Comments bool
+	// Source 返回配置项的来源，作为行尾注释
+	Source func(key string) string
+	// Redact 隐藏敏感配置项以及值来自引用的配置项
+	Redact bool
+	// Refs 值来自引用的配置项，Redact 时一并隐藏
+	Refs References
+}
+
+// Render 将配置渲染为 YAML
+func Render(cfg *AppConfig, opts RenderOptions) ([]byte, error) {
+	doc := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{
+		renderNode(reflect.ValueOf(*cfg), "", opts),
+	}}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// renderNode 按 mapstructure 键名构建 YAML 节点
+func renderNode(val reflect.Value, path string, opts RenderOptions) *yaml.Node {
+	switch {
+	case val.Type() == reflect.TypeOf(time.Duration(0)):
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: formatDuration(val.Interface().(time.Duration))}
+	case val.Kind() == reflect.Struct:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		t := val.Type()
+		for i := 0; i < t.NumField(); i++ {
+			name := t.Field(i).Tag.Get("mapstructure")
+			if name == "" {
+				continue
+			}
+			key := joinKey(path, name)
+			keyNode := &yaml.Node{Kind: yaml.ScalarNode, Value: name}
+			valueNode := renderNode(val.Field(i), key, opts)
+			if opts.Comments {
+				keyNode.HeadComment = fieldComment(key, t.Field(i))
+			}
+			if opts.Redact && (IsSecret(key) || opts.Refs.Has(key)) && !val.Field(i).IsZero() {
+				valueNode = &yaml.Node{Kind: yaml.ScalarNode, Value: redacted}
+			}
+			// 嵌套结构的来源标注在各子项上
+			if opts.Source != nil && (valueNode.Kind != yaml.MappingNode || val.Field(i).Kind() == reflect.Map) {
+				comment := "# " + opts.Source(key)
+				if valueNode.Kind != yaml.ScalarNode && valueNode.Style&yaml.FlowStyle == 0 {
+					keyNode.LineComment = comment
+				} else {
+					valueNode.LineComment = comment
+				}
+			}
+			node.Content = append(node.Content, keyNode, valueNode)
+		}
+		return node
+	case val.Kind() == reflect.Slice:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		if val.Len() == 0 {
+			node.Style = yaml.FlowStyle
+		}
+		// 切片整体标注来源，元素内不再逐项标注
+		itemOpts := opts
+		itemOpts.Source = nil
+		for i := 0; i < val.Len(); i++ {
+			node.Content = append(node.Content, renderNode(val.Index(i), path, itemOpts))
+		}
+		return node
+	case val.Kind() == reflect.Map && val.Type().Key().Kind() == reflect.String:
+		// 逐项渲染，以便隐藏 headers 等映射中的敏感值
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		if val.Len() == 0 {
+			node.Style = yaml.FlowStyle
+		}
+		keys := make([]string, 0, val.Len())
+		for _, k := range val.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		sort.Strings(keys)
+		for _, name := range keys {
+			item := val.MapIndex(reflect.ValueOf(name).Convert(val.Type().Key()))
+			key := joinKey(path, strings.ToLower(name))
+			valueNode := renderNode(item, key, opts)
+			if opts.Redact && (IsSecret(key) || opts.Refs.Has(key)) && !item.IsZero() {
+				valueNode = &yaml.Node{Kind: yaml.ScalarNode, Value: redacted}
+			}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, valueNode)
+		}
+		return node
+	default:
+		node := &yaml.Node{}
+		node.Encode(val.Interface())
+		return node
+	}
+}
+
+// formatDuration 省略末尾为零的单位，如 720h0m0s 输出为 720h
+func formatDuration(d time.Duration) string {
+	text := d.String()
+	if strings.HasSuffix(text, "m0s") {
+		text = strings.TrimSuffix(text, "0s")
+	}
+	if strings.HasSuffix(text, "h0m") {
+		text = strings.TrimSuffix(text, "0m")
+	}
+	return text
+}
+
+// fieldComment 返回配置项说明，枚举项附带可选值
+func fieldComment(key string, field reflect.StructField) string {
+	comment, ok := i18n.Lookup(i18n.Language(), fieldKey(key))
+	if !ok {
+		comment, _ = i18n.Lookup(i18n.Languages[0], fieldKey(key))
+	}
+	for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+		if values, ok := strings.CutPrefix(rule, "oneof="); ok {
+			comment += i18n.T("cli.config.options", strings.ReplaceAll(values, " ", " | "))
+		}
+	}
+	return comment
+}
+
+// fieldKey 返回配置项说明的消息键
+func fieldKey(key string) string {
+	return "cli.config.fields." + key
+}
+
+// MessageKeys 返回各配置项说明与配置校验规则使用的消息键，供 i18n check 检查语言包是否完整
+func MessageKeys() []string {
+	keys := map[string]bool{}
+	for _, tag := range structRules {
+		keys[ruleKey(tag)] = true
+	}
+	messageKeys(reflect.TypeOf(AppConfig{}), "", true, keys)
+	list := make([]string, 0, len(keys))
+	for key := range keys {
+		list = append(list, key)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// messageKeys 收集 t 中各字段的校验规则键，comments 为 true 时同时收集说明键（切片元素中的字段没有说明）
+func messageKeys(t reflect.Type, path string, comments bool, keys map[string]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := field.Tag.Get("mapstructure")
+		if name == "" {
+			continue
+		}
+		key := joinKey(path, name)
+		if comments {
+			keys[fieldKey(key)] = true
+		}
+		for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+			if tag, _, _ := strings.Cut(rule, "="); tag != "" && tag != "omitempty" && tag != "dive" {
+				keys[ruleKey(tag)] = true
+			}
+		}
+		switch ft := field.Type; {
+		case ft.Kind() == reflect.Slice && ft.Elem().Kind() == reflect.Struct:
+			messageKeys(ft.Elem(), key, false, keys)
+		case ft.Kind() == reflect.Struct && ft.PkgPath() == t.PkgPath():
+			messageKeys(ft, key, comments, keys)
+		}
+	}
+}
+
+// Setting 一个展开后的配置项
+type Setting struct {
+	Key   string
+	Value string
+	// Display 隐藏其中敏感信息与引用值后的 Value，用于展示
+	Display string
+}
+
+// Flatten 将配置展开为按声明顺序排列的键值列表，切片整体作为一项，refs 为值来自引用的配置项
+func Flatten(cfg *AppConfig, refs References) []Setting {
+	var settings []Setting
+	flatten(reflect.ValueOf(*cfg), "", refs, &settings)
+	return settings
+}
+
+func flatten(val reflect.Value, path string, refs References, settings *[]Setting) {
+	if val.Kind() == reflect.Struct {
+		t := val.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if name := t.Field(i).Tag.Get("mapstructure"); name != "" {
+				flatten(val.Field(i), joinKey(path, name), refs, settings)
+			}
+		}
+		return
+	}
+	*settings = append(*settings, Setting{
+		Key:     path,
+		Value:   flowYAML(renderNode(val, path, RenderOptions{})),
+		Display: flowYAML(renderNode(val, path, RenderOptions{Redact: true, Refs: refs})),
+	})
+}
+
+// flowYAML 将节点输出为单行 YAML
+func flowYAML(node *yaml.Node) string {
+	setFlow(node)
+	data, _ := yaml.Marshal(node)
+	return strings.TrimSpace(string(data))
+}
+
+// setFlow 使用单行的流式风格输出
+func setFlow(node *yaml.Node) {
+	node.Style |= yaml.FlowStyle
+	for _, child := range node.Content {
+		setFlow(child)
+	}
+}
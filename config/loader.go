@@ -0,0 +1,246 @@
+package config
+
+import (
+	"HarborArk/internal/i18n"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// 环境变量前缀，如 HARBORARK_SERVER_PORT 对应 server.port
+const envPrefix = "HARBORARK"
+
+// Options 配置加载选项
+//
+// 配置项优先级从高到低：命令行标志 > HARBORARK_* 环境变量 > 配置文件 > 运行环境默认值 > 内置默认值
+type Options struct {
+	// File 配置文件路径，为空时依次使用 HARBORARK_CONFIG 和 config/settings-<profile>.yaml
+	File string
+	// Profile 运行环境，为空时使用 HARBORARK_PROFILE，默认 dev
+	Profile Runmode
+	// Flags 配置键到命令行标志的绑定，只有显式指定的标志才会覆盖配置
+	Flags map[string]*pflag.Flag
+}
+
+var (
+	// vp 服务运行时使用的 viper 实例，包含环境变量与命令行绑定
+	vp *viper.Viper
+	// current 当前生效的配置，热加载时整体替换
+	current       atomic.Pointer[AppConfig]
+	currentRefs   atomic.Pointer[References]
+	loadedFile    string
+	loadedProfile Runmode
+	boundFlags    map[string]*pflag.Flag
+)
+
+// profileModes 各运行环境默认的 Gin 模式
+var profileModes = map[Runmode]string{
+	RunmodeDev:  "debug",
+	RunmodeTest: "test",
+	RunmodeProd: "release",
+}
+
+// ParseRunmode 解析运行环境，空字符串视为 dev
+func ParseRunmode(s string) (Runmode, error) {
+	if s == "" {
+		return RunmodeDev, nil
+	}
+	mode := Runmode(strings.ToLower(s))
+	if _, ok := profileModes[mode]; !ok {
+		return "", i18n.NewError("errors.config.invalid_profile", s)
+	}
+	return mode, nil
+}
+
+// FilePath 返回实际使用的配置文件路径与运行环境
+func FilePath(file string, profile Runmode) (string, Runmode, error) {
+	if profile == "" {
+		profile = Runmode(os.Getenv(envPrefix + "_PROFILE"))
+	}
+	profile, err := ParseRunmode(string(profile))
+	if err != nil {
+		return "", "", err
+	}
+	if file == "" {
+		file = os.Getenv(envPrefix + "_CONFIG")
+	}
+	if file == "" {
+		file = filepath.Join("config", fmt.Sprintf("settings-%s.yaml", profile))
+	}
+	return file, profile, nil
+}
+
+// Init 按优先级合并命令行标志、环境变量、配置文件与默认值，初始化 Config
+func Init(opts Options) error {
+	file, profile, err := FilePath(opts.File, opts.Profile)
+	if err != nil {
+		return err
+	}
+	v, err := newViper(file, profile)
+	if err != nil {
+		return err
+	}
+
+	bindEnv(v)
+	for key, flag := range opts.Flags {
+		if flag == nil {
+			continue
+		}
+		if err := v.BindPFlag(key, flag); err != nil {
+			return i18n.NewError("errors.config.bind_flag_failed", flag.Name, err)
+		}
+	}
+	boundFlags = opts.Flags
+
+	cfg, refs, err := decode(v, file)
+	if err != nil {
+		return err
+	}
+
+	vp = v
+	current.Store(cfg)
+	currentRefs.Store(&refs)
+	loadedFile = file
+	loadedProfile = profile
+	return nil
+}
+
+// LoadFile 只根据配置文件与默认值解析配置，不读取环境变量与命令行，用于检查和比较配置文件。
+// 同时返回值来自引用的配置项；校验失败时同时返回解析出的配置与 *ValidationError
+func LoadFile(file string, profile Runmode) (*AppConfig, References, error) {
+	v, err := newViper(file, profile)
+	if err != nil {
+		return nil, nil, err
+	}
+	return decode(v, file)
+}
+
+// ReadAuditConfig 读取审计日志配置，不展开其他配置项中的引用，供修改配置、密钥与证书的命令记录审计日志
+func ReadAuditConfig(file string, profile Runmode) (AuditConfig, error) {
+	cfg := Default().Audit
+	v, err := newViper(file, profile)
+	if err != nil {
+		return cfg, err
+	}
+	bindEnv(v)
+	if err := v.UnmarshalKey("audit", &cfg); err != nil {
+		return cfg, i18n.NewError("errors.config.audit_failed", err)
+	}
+	return cfg, nil
+}
+
+// ProfileDefault 返回运行环境对应的默认配置
+func ProfileDefault(profile Runmode) *AppConfig {
+	cfg := Default()
+	if mode, ok := profileModes[profile]; ok {
+		cfg.Server.Mode = mode
+	}
+	return cfg
+}
+
+// newViper 创建登记了默认值并读取了配置文件的 viper 实例
+func newViper(file string, profile Runmode) (*viper.Viper, error) {
+	v := viper.New()
+	setDefaults(v, "", reflect.ValueOf(*ProfileDefault(profile)))
+	v.SetConfigFile(file)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, i18n.NewError("errors.config.read_file_failed", file, err)
+	}
+	return v, nil
+}
+
+// bindEnv 允许 HARBORARK_* 环境变量覆盖已登记的配置项
+func bindEnv(v *viper.Viper) {
+	v.SetEnvPrefix(envPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+}
+
+// decode 展开引用后解析并校验配置，解析错误、引用错误、校验错误与未知配置项一并返回。
+// 只要能够解析，即使校验失败也返回配置
+func decode(v *viper.Viper, file string) (*AppConfig, References, error) {
+	r := &resolver{config: Default().Secrets}
+	if err := v.UnmarshalKey("secrets", &r.config); err != nil {
+		return nil, nil, i18n.NewError("errors.config.secrets_failed", err)
+	}
+
+	cfg := &AppConfig{}
+	var problems []Problem
+	if err := v.Unmarshal(cfg, viper.DecodeHook(r.decodeHook())); err != nil {
+		if problems = decodeProblems(err); len(problems) == 0 {
+			return nil, nil, i18n.NewError("errors.config.parse_failed", err)
+		}
+	}
+	return cfg, findReferences(v), checkFile(file, cfg, problems)
+}
+
+// Get 返回当前生效的配置，未初始化时返回 nil。返回值只读，热加载会替换为新的实例
+func Get() *AppConfig {
+	return current.Load()
+}
+
+// Refs 返回当前生效配置中值来自引用的配置项
+func Refs() References {
+	if refs := currentRefs.Load(); refs != nil {
+		return *refs
+	}
+	return nil
+}
+
+// Source 返回配置项在当前生效配置中的来源
+func Source(key string) string {
+	if ref, ok := Refs()[strings.ToLower(key)]; ok {
+		return i18n.T("cli.config.source.ref", ref)
+	}
+	if flag := boundFlags[key]; flag != nil && flag.Changed {
+		return i18n.T("cli.config.source.flag", flag.Name)
+	}
+	if env := envName(key); os.Getenv(env) != "" {
+		return i18n.T("cli.config.source.env", env)
+	}
+	if vp != nil && vp.InConfig(key) {
+		return i18n.T("cli.config.source.file")
+	}
+	if key == "server.mode" {
+		return i18n.T("cli.config.source.profile_default", loadedProfile)
+	}
+	return i18n.T("cli.config.source.default")
+}
+
+// File 返回当前加载的配置文件路径
+func File() string {
+	return loadedFile
+}
+
+// Profile 返回当前运行环境
+func Profile() Runmode {
+	return loadedProfile
+}
+
+// setDefaults 将默认配置逐项注册到 viper。只有已知的键才能被环境变量覆盖，
+// 因此配置文件中未出现的项也需要在这里登记
+func setDefaults(v *viper.Viper, prefix string, val reflect.Value) {
+	t := val.Type()
+	for i := 0; i < t.NumField(); i++ {
+		key := t.Field(i).Tag.Get("mapstructure")
+		if key == "" {
+			continue
+		}
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		field := val.Field(i)
+		if field.Kind() == reflect.Struct && field.Type() != reflect.TypeOf(time.Time{}) {
+			setDefaults(v, key, field)
+			continue
+		}
+		v.SetDefault(key, field.Interface())
+	}
+}
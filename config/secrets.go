@@ -0,0 +1,139 @@
+package config
+
+import (
+	"HarborArk/internal/i18n"
+	"HarborArk/internal/secrets"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/viper"
+)
+
+// refPattern 匹配配置值中的引用：${file:path}、${env:VAR} 与 ${secret:name}
+var refPattern = regexp.MustCompile(`\$\{(file|env|secret):([^}]+)\}`)
+
+// References 配置项（小写路径，序列元素不区分下标）到原始引用的映射
+type References map[string]string
+
+// Has 判断配置项的值是否来自引用
+func (r References) Has(key string) bool {
+	_, ok := r[strings.ToLower(key)]
+	return ok
+}
+
+// resolver 在解析配置时展开引用，密钥库在第一次遇到 ${secret:...} 时才打开
+type resolver struct {
+	config   SecretsConfig
+	keystore *secrets.Keystore
+	err      error
+	opened   bool
+}
+
+// decodeHook 展开字符串中的引用，随后按 viper 的默认规则转换时间与切片
+func (r *resolver) decodeHook() mapstructure.DecodeHookFunc {
+	return mapstructure.ComposeDecodeHookFunc(
+		func(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+			if s, ok := data.(string); ok && strings.Contains(s, "${") {
+				return r.expand(s)
+			}
+			return data, nil
+		},
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+	)
+}
+
+// expand 替换 s 中的全部引用，任一引用无法解析时返回错误
+func (r *resolver) expand(s string) (string, error) {
+	var firstErr error
+	value := refPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		m := refPattern.FindStringSubmatch(ref)
+		value, err := r.lookup(m[1], m[2])
+		if err != nil && firstErr == nil {
+			firstErr = i18n.NewError("errors.config.resolve_failed", ref, err)
+		}
+		return value
+	})
+	return value, firstErr
+}
+
+func (r *resolver) lookup(kind, name string) (string, error) {
+	switch kind {
+	case "file":
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return "", err
+		}
+		// 密钥文件通常以换行结尾
+		return strings.TrimRight(string(data), "\r\n"), nil
+	case "env":
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", i18n.NewError("errors.config.env_not_set", name)
+		}
+		return value, nil
+	default:
+		ks, err := r.open()
+		if err != nil {
+			return "", err
+		}
+		return ks.Get(name)
+	}
+}
+
+// open 打开密钥库，结果会被缓存，避免每个引用都重新派生密钥
+func (r *resolver) open() (*secrets.Keystore, error) {
+	if !r.opened {
+		r.opened = true
+		var key []byte
+		if key, _, r.err = secrets.MasterKey(r.config.KeyFile); r.err == nil {
+			r.keystore, r.err = secrets.Open(r.config.Keystore, key)
+		}
+	}
+	return r.keystore, r.err
+}
+
+// findReferences 找出值中包含引用的配置项
+func findReferences(v *viper.Viper) References {
+	refs := References{}
+	var walk func(path string, val interface{})
+	walk = func(path string, val interface{}) {
+		switch val := val.(type) {
+		case map[string]interface{}:
+			for key, item := range val {
+				walk(joinKey(path, strings.ToLower(key)), item)
+			}
+		case []interface{}:
+			for _, item := range val {
+				walk(path, item)
+			}
+		case []string:
+			for _, item := range val {
+				walk(path, item)
+			}
+		case string:
+			if refPattern.MatchString(val) {
+				refs[path] = val
+			}
+		}
+	}
+	walk("", v.AllSettings())
+	return refs
+}
+
+// ReadSecretsConfig 读取密钥库配置，不展开其他配置项中的引用，供密钥库尚未建立时的命令使用
+func ReadSecretsConfig(file string, profile Runmode) (SecretsConfig, error) {
+	cfg := Default().Secrets
+	v, err := newViper(file, profile)
+	if err != nil {
+		return cfg, err
+	}
+	bindEnv(v)
+	if err := v.UnmarshalKey("secrets", &cfg); err != nil {
+		return cfg, i18n.NewError("errors.config.secrets_failed", err)
+	}
+	return cfg, nil
+}
@@ -1,9 +1,7 @@
 package config
 
 import (
-	"fmt"
-
-	"github.com/spf13/viper"
+	"time"
 )
 
 // AppConfig 应用配置
@@ -11,22 +9,138 @@ type AppConfig struct {
 	Server  ServerConfig  `mapstructure:"server"`
 	Logger  LogConfig     `mapstructure:"logger"`
 	Swagger SwaggerConfig `mapstructure:"swagger"`
+	Webhook WebhookConfig `mapstructure:"webhook"`
+	Audit   AuditConfig   `mapstructure:"audit"`
+	// AccessLog 访问日志
+	AccessLog AccessLogConfig `mapstructure:"accessLog"`
+	Metrics   MetricsConfig   `mapstructure:"metrics"`
+	Storage   StorageConfig   `mapstructure:"storage"`
+	Tracing   TracingConfig   `mapstructure:"tracing"`
+	Health    HealthConfig    `mapstructure:"health"`
+	Secrets   SecretsConfig   `mapstructure:"secrets"`
+	// RateLimit 请求限流与登录失败锁定
+	RateLimit RateLimitConfig `mapstructure:"rateLimit"`
+	// Security 跨域、安全响应头与 CSRF 防护
+	Security SecurityConfig `mapstructure:"security"`
 }
 
 // ServerConfig 服务器配置
 type ServerConfig struct {
-	Port string `mapstructure:"port"`
-	Mode string `mapstructure:"mode"`
+	Port            string        `mapstructure:"port" validate:"port"`
+	Mode            string        `mapstructure:"mode" validate:"oneof=debug release test"`
+	ShutdownTimeout time.Duration `mapstructure:"shutdownTimeout" validate:"gt=0"`
+	TLS             TLSConfig     `mapstructure:"tls"`
+	// TrustedProxies 可信反向代理的 IP 或网段，只有来自这些地址的请求才从 RemoteIPHeaders 中读取客户端 IP
+	TrustedProxies []string `mapstructure:"trustedProxies" validate:"dive,cidr|ip"`
+	// RemoteIPHeaders 携带客户端 IP 的请求头，按顺序使用第一个有效的
+	RemoteIPHeaders []string `mapstructure:"remoteIPHeaders" validate:"dive,required"`
+}
+
+// TLSConfig HTTPS 配置
+type TLSConfig struct {
+	Enabled      bool               `mapstructure:"enabled"`
+	CertFile     string             `mapstructure:"certFile"`
+	KeyFile      string             `mapstructure:"keyFile"`
+	MinVersion   string             `mapstructure:"minVersion" validate:"omitempty,oneof=1.2 1.3"`
+	CipherSuites []string           `mapstructure:"cipherSuites"`
+	RedirectHTTP bool               `mapstructure:"redirectHTTP"`
+	HTTPPort     string             `mapstructure:"httpPort" validate:"port"`
+	ClientAuth   string             `mapstructure:"clientAuth" validate:"omitempty,oneof=none request require"`
+	ClientCAFile string             `mapstructure:"clientCAFile"`
+	ClientUsers  []ClientUserConfig `mapstructure:"clientUsers" validate:"dive"`
+	ACME         ACMEConfig         `mapstructure:"acme"`
+}
+
+// ACMEConfig ACME 自动证书配置
+type ACMEConfig struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	Email           string        `mapstructure:"email" validate:"omitempty,email"`
+	Domains         []string      `mapstructure:"domains" validate:"dive,hostname_rfc1123"`
+	DirectoryURL    string        `mapstructure:"directoryURL" validate:"url"`
+	DirectoryCAFile string        `mapstructure:"directoryCAFile"`
+	CacheDir        string        `mapstructure:"cacheDir" validate:"required"`
+	RenewBefore     time.Duration `mapstructure:"renewBefore" validate:"gt=0"`
+}
+
+// ClientUserConfig 客户端证书与用户的映射
+type ClientUserConfig struct {
+	CommonName string `mapstructure:"commonName" validate:"required"`
+	User       string `mapstructure:"user" validate:"required"`
+	// Role 用户角色，admin 可以访问管理与审计接口
+	Role string `mapstructure:"role" validate:"omitempty,oneof=admin user"`
+}
+
+// AdminAuth 判断是否配置了管理员认证：启用 TLS 并校验客户端证书，且至少一个证书映射为 admin 角色。
+// 未配置时不提供管理与审计接口
+func (c TLSConfig) AdminAuth() bool {
+	if !c.Enabled || c.ClientAuth == "" || c.ClientAuth == "none" || c.ClientCAFile == "" {
+		return false
+	}
+	for _, u := range c.ClientUsers {
+		if u.Role == "admin" {
+			return true
+		}
+	}
+	return false
 }
 
 // LogConfig 日志配置
 type LogConfig struct {
-	Level      string `mapstructure:"level"`
-	Encoding   string `mapstructure:"encoding"`
-	Filename   string `mapstructure:"filename"`
-	MaxSize    int    `mapstructure:"maxSize"`
-	MaxAge     int    `mapstructure:"maxAge"`
-	MaxBackups int    `mapstructure:"maxBackups"`
+	Level      string `mapstructure:"level" validate:"oneof=debug info warn error dpanic panic fatal"`
+	Encoding   string `mapstructure:"encoding" validate:"oneof=json console"`
+	Filename   string `mapstructure:"filename" validate:"required"`
+	MaxSize    int    `mapstructure:"maxSize" validate:"gt=0"`
+	MaxAge     int    `mapstructure:"maxAge" validate:"gte=0"`
+	MaxBackups int    `mapstructure:"maxBackups" validate:"gte=0"`
+	Compress   bool   `mapstructure:"compress"`
+	// Levels 按组件名称单独设置的日志级别，如 webhook: debug
+	Levels map[string]string `mapstructure:"levels" validate:"dive,oneof=debug info warn error dpanic panic fatal"`
+	// Sinks 日志文件之外的输出，各自设置级别与编码
+	Sinks []LogSinkConfig `mapstructure:"sinks" validate:"dive"`
+	// Redact 写入日志前隐藏的敏感信息
+	Redact RedactConfig `mapstructure:"redact"`
+}
+
+// RedactConfig 日志脱敏配置，名称不区分大小写并忽略 - 与 _，如 access_token 同样匹配 accessToken
+type RedactConfig struct {
+	// Headers 隐藏值的请求头与响应头
+	Headers []string `mapstructure:"headers"`
+	// Query 隐藏值的查询参数与表单字段
+	Query []string `mapstructure:"query"`
+	// Fields 隐藏值的 JSON 请求体字段与日志字段，包括嵌套对象中的字段
+	Fields []string `mapstructure:"fields"`
+	Mask   string   `mapstructure:"mask" validate:"required"`
+}
+
+// LogSinkConfig 日志输出配置，未设置的项在创建输出时使用默认值
+type LogSinkConfig struct {
+	// Name 输出名称，用于指标标签与错误提示，默认为 类型-序号
+	Name string `mapstructure:"name"`
+	Type string `mapstructure:"type" validate:"oneof=stdout syslog http"`
+	// Level 该输出的最低级别，留空时输出全局级别允许的全部日志
+	Level    string `mapstructure:"level" validate:"omitempty,oneof=debug info warn error dpanic panic fatal"`
+	Encoding string `mapstructure:"encoding" validate:"omitempty,oneof=json console journald"`
+
+	// syslog：RFC 5424 格式
+	Network  string `mapstructure:"network" validate:"omitempty,oneof=udp tcp unix unixgram"`
+	Address  string `mapstructure:"address" validate:"required_if=Type syslog"`
+	Facility string `mapstructure:"facility" validate:"omitempty,oneof=kern user mail daemon auth syslog lpr news uucp cron authpriv ftp local0 local1 local2 local3 local4 local5 local6 local7"`
+	AppName  string `mapstructure:"appName"`
+
+	// http：批量推送到 Loki 或 Elasticsearch
+	URL           string            `mapstructure:"url" validate:"required_if=Type http,omitempty,url"`
+	Format        string            `mapstructure:"format" validate:"omitempty,oneof=loki elasticsearch"`
+	Index         string            `mapstructure:"index"`
+	Labels        map[string]string `mapstructure:"labels"`
+	Headers       map[string]string `mapstructure:"headers"`
+	Username      string            `mapstructure:"username"`
+	Password      string            `mapstructure:"password"`
+	BatchSize     int               `mapstructure:"batchSize" validate:"gte=0,lte=10000"`
+	BufferSize    int               `mapstructure:"bufferSize" validate:"gte=0"`
+	FlushInterval time.Duration     `mapstructure:"flushInterval" validate:"gte=0"`
+	Timeout       time.Duration     `mapstructure:"timeout" validate:"gte=0"`
+	MaxRetries    int               `mapstructure:"maxRetries" validate:"gte=0,lte=10"`
+	Overflow      string            `mapstructure:"overflow" validate:"omitempty,oneof=drop block"`
 }
 
 // SwaggerConfig Swagger配置
@@ -34,73 +148,308 @@ type SwaggerConfig struct {
 	Title       string   `mapstructure:"title"`
 	Description string   `mapstructure:"description"`
 	Version     string   `mapstructure:"version"`
-	Host        string   `mapstructure:"host"`
-	BasePath    string   `mapstructure:"basePath"`
+	Host        string   `mapstructure:"host" validate:"omitempty,hostname_port"`
+	BasePath    string   `mapstructure:"basePath" validate:"startswith=/"`
 	Enabled     bool     `mapstructure:"enabled"`
 	AutoUpdate  bool     `mapstructure:"autoUpdate"`
-	OutputDir   string   `mapstructure:"outputDir"`
-	MainApiFile string   `mapstructure:"mainApiFile"`
-	Schemes     []string `mapstructure:"schemes"`
+	OutputDir   string   `mapstructure:"outputDir" validate:"required_if=AutoUpdate true"`
+	MainApiFile string   `mapstructure:"mainApiFile" validate:"required_if=AutoUpdate true"`
+	Schemes     []string `mapstructure:"schemes" validate:"dive,oneof=http https"`
 }
 
-var Config *AppConfig
+// WebhookConfig Webhook 推送配置
+type WebhookConfig struct {
+	Enabled        bool          `mapstructure:"enabled"`
+	StoreFile      string        `mapstructure:"storeFile" validate:"required_if=Enabled true"`
+	Workers        int           `mapstructure:"workers" validate:"min=1,max=64"`
+	MaxAttempts    int           `mapstructure:"maxAttempts" validate:"min=1,max=100"`
+	InitialBackoff time.Duration `mapstructure:"initialBackoff" validate:"gt=0"`
+	MaxBackoff     time.Duration `mapstructure:"maxBackoff" validate:"gtefield=InitialBackoff"`
+	Timeout        time.Duration `mapstructure:"timeout" validate:"gt=0"`
+	HistoryLimit   int           `mapstructure:"historyLimit" validate:"gte=0"`
+}
 
-// Init 初始化配置
-func Init() error {
-	viper.SetConfigFile("config/settings-dev.yaml")
-	if err := viper.ReadInConfig(); err != nil {
-		return fmt.Errorf("读取配置文件失败: %v", err)
-	}
+// AuditConfig 审计日志配置
+type AuditConfig struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	Filename string `mapstructure:"filename" validate:"required_if=Enabled true"`
+}
 
-	Config = &AppConfig{}
-	if err := viper.Unmarshal(Config); err != nil {
-		return fmt.Errorf("解析配置文件失败: %v", err)
-	}
+// AccessLogConfig 访问日志配置，与应用日志分开写入
+type AccessLogConfig struct {
+	Enabled    bool   `mapstructure:"enabled"`
+	Format     string `mapstructure:"format" validate:"oneof=combined common json"`
+	Filename   string `mapstructure:"filename" validate:"required_if=Enabled true"`
+	MaxSize    int    `mapstructure:"maxSize" validate:"gt=0"`
+	MaxAge     int    `mapstructure:"maxAge" validate:"gte=0"`
+	MaxBackups int    `mapstructure:"maxBackups" validate:"gte=0"`
+	Compress   bool   `mapstructure:"compress"`
+	// Skip 不记录的路径，以 * 结尾时按前缀匹配
+	Skip []string `mapstructure:"skip" validate:"dive,startswith=/"`
+	// Sampling 按路径采样，使用第一条匹配的规则，状态码 >= 400 的请求总是记录
+	Sampling []AccessLogSampling `mapstructure:"sampling" validate:"dive"`
+}
+
+// AccessLogSampling 访问日志采样规则
+type AccessLogSampling struct {
+	// Path 路径，以 * 结尾时按前缀匹配
+	Path string `mapstructure:"path" validate:"startswith=/"`
+	// Rate 记录的比例，0 表示只记录出错的请求
+	Rate float64 `mapstructure:"rate" validate:"gte=0,lte=1"`
+}
+
+// MetricsConfig Prometheus 指标配置
+type MetricsConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Path    string `mapstructure:"path" validate:"startswith=/"`
+}
+
+// StorageConfig 存储配置
+type StorageConfig struct {
+	Volumes []VolumeConfig `mapstructure:"volumes" validate:"unique=Name,dive"`
+}
+
+// VolumeConfig 存储卷配置
+type VolumeConfig struct {
+	Name string `mapstructure:"name" validate:"required"`
+	Path string `mapstructure:"path" validate:"required"`
+}
+
+// TracingConfig OpenTelemetry 链路追踪配置
+type TracingConfig struct {
+	Enabled     bool    `mapstructure:"enabled"`
+	ServiceName string  `mapstructure:"serviceName" validate:"required"`
+	Endpoint    string  `mapstructure:"endpoint" validate:"omitempty,url"`
+	SampleRatio float64 `mapstructure:"sampleRatio" validate:"gte=0,lte=1"`
+}
+
+// HealthConfig 健康检查配置
+type HealthConfig struct {
+	Timeout         time.Duration `mapstructure:"timeout" validate:"gt=0"`
+	MinFreePercent  float64       `mapstructure:"minFreePercent" validate:"gte=0,lte=100"`
+	MinCertValidity time.Duration `mapstructure:"minCertValidity" validate:"gte=0"`
+}
+
+// SecretsConfig 密钥库配置，配置值中的 ${secret:name} 从密钥库读取
+type SecretsConfig struct {
+	Keystore string `mapstructure:"keystore" validate:"required"`
+	KeyFile  string `mapstructure:"keyFile"`
+}
+
+// RateLimitConfig 请求限流配置，作用于 /api 下的全部接口，同一请求需同时满足各项限额
+type RateLimitConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// IP 每个客户端 IP 的限额，IPv6 地址按 /64 网段计数
+	IP RateLimitRule `mapstructure:"ip"`
+	// User 每个已认证用户的限额
+	User RateLimitRule `mapstructure:"user"`
+	// Routes 按路由分组的限额，使用第一条匹配的规则
+	Routes []RouteRateLimit `mapstructure:"routes" validate:"dive"`
+	// Exempt 不限流的客户端 IP 或网段
+	Exempt  []string      `mapstructure:"exempt" validate:"dive,cidr|ip"`
+	Lockout LockoutConfig `mapstructure:"lockout"`
+}
+
+// RateLimitRule 令牌桶限额，每个周期补充 Limit 个令牌，桶容量为 Burst
+type RateLimitRule struct {
+	// Limit 每个周期允许的请求数，0 表示不限制
+	Limit  int           `mapstructure:"limit" validate:"gte=0"`
+	Period time.Duration `mapstructure:"period" validate:"gt=0"`
+	// Burst 允许的突发请求数，0 表示与 Limit 相同
+	Burst int `mapstructure:"burst" validate:"gte=0"`
+}
+
+// RouteRateLimit 路由分组的限额
+type RouteRateLimit struct {
+	// Path 路径，以 * 结尾时按前缀匹配
+	Path string `mapstructure:"path" validate:"startswith=/"`
+	// Key 计数维度，user 时未认证的请求按 IP 计数
+	Key    string        `mapstructure:"key" validate:"oneof=ip user"`
+	Limit  int           `mapstructure:"limit" validate:"gte=0"`
+	Period time.Duration `mapstructure:"period" validate:"gt=0"`
+	Burst  int           `mapstructure:"burst" validate:"gte=0"`
+}
 
-	return nil
+// Rule 返回路由分组的令牌桶限额
+func (r RouteRateLimit) Rule() RateLimitRule {
+	return RateLimitRule{Limit: r.Limit, Period: r.Period, Burst: r.Burst}
+}
+
+// LockoutConfig 登录失败锁定配置，同一用户或 IP 连续失败后锁定，再次锁定时时长加倍
+type LockoutConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// MaxFailures 触发锁定的失败次数
+	MaxFailures int `mapstructure:"maxFailures" validate:"gt=0"`
+	// Window 失败次数的统计窗口，超过窗口未再失败时重新计数
+	Window time.Duration `mapstructure:"window" validate:"gt=0"`
+	// Duration 首次锁定时长
+	Duration    time.Duration `mapstructure:"duration" validate:"gt=0"`
+	MaxDuration time.Duration `mapstructure:"maxDuration" validate:"gtefield=Duration"`
+}
+
+// SecurityConfig 浏览器相关的安全配置
+type SecurityConfig struct {
+	CORS    CORSConfig            `mapstructure:"cors"`
+	Headers SecurityHeadersConfig `mapstructure:"headers"`
+	CSRF    CSRFConfig            `mapstructure:"csrf"`
+}
+
+// CORSConfig 跨域资源共享配置
+type CORSConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// AllowOrigins 允许的来源，如 https://nas.example.com，https://*.example.com 匹配全部子域名，* 匹配任意来源
+	AllowOrigins []string `mapstructure:"allowOrigins" validate:"dive,origin"`
+	AllowMethods []string `mapstructure:"allowMethods" validate:"dive,required"`
+	AllowHeaders []string `mapstructure:"allowHeaders" validate:"dive,required"`
+	// ExposeHeaders 允许浏览器脚本读取的响应头
+	ExposeHeaders []string `mapstructure:"exposeHeaders" validate:"dive,required"`
+	// AllowCredentials 允许携带 Cookie 与客户端证书，不能与 * 来源同时使用
+	AllowCredentials bool `mapstructure:"allowCredentials"`
+	// MaxAge 浏览器缓存预检结果的时长
+	MaxAge time.Duration `mapstructure:"maxAge" validate:"gte=0"`
+}
+
+// SecurityHeadersConfig 安全响应头配置，值为空时不发送对应的响应头
+type SecurityHeadersConfig struct {
+	Enabled               bool   `mapstructure:"enabled"`
+	ContentSecurityPolicy string `mapstructure:"contentSecurityPolicy"`
+	// HSTSMaxAge Strict-Transport-Security 的有效期，只在 HTTPS 请求中发送，0 表示不发送
+	HSTSMaxAge            time.Duration `mapstructure:"hstsMaxAge" validate:"gte=0"`
+	HSTSIncludeSubdomains bool          `mapstructure:"hstsIncludeSubdomains"`
+	HSTSPreload           bool          `mapstructure:"hstsPreload"`
+	FrameOptions          string        `mapstructure:"frameOptions" validate:"omitempty,oneof=DENY SAMEORIGIN"`
+	ReferrerPolicy        string        `mapstructure:"referrerPolicy" validate:"omitempty,oneof=no-referrer no-referrer-when-downgrade origin origin-when-cross-origin same-origin strict-origin strict-origin-when-cross-origin unsafe-url"`
+	// ContentTypeNosniff 发送 X-Content-Type-Options: nosniff
+	ContentTypeNosniff bool `mapstructure:"contentTypeNosniff"`
+}
+
+// CSRFConfig 双重提交 Cookie 的 CSRF 防护配置，只校验通过 Cookie 认证的请求
+type CSRFConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// CookieName 保存令牌的 Cookie，脚本需能读取，因此不设置 HttpOnly
+	CookieName string `mapstructure:"cookieName" validate:"required"`
+	// HeaderName 提交令牌的请求头，其值需与 Cookie 一致
+	HeaderName string `mapstructure:"headerName" validate:"required"`
+	// SessionCookies 表示 Cookie 认证的 Cookie，留空时携带令牌 Cookie 之外任意 Cookie 的请求都需要校验
+	SessionCookies []string `mapstructure:"sessionCookies" validate:"dive,required"`
+	SameSite       string   `mapstructure:"sameSite" validate:"oneof=strict lax none"`
+	// MaxAge 令牌 Cookie 的有效期
+	MaxAge time.Duration `mapstructure:"maxAge" validate:"gt=0"`
 }
 
 // GetLogConfig 获取日志配置
 func GetLogConfig() LogConfig {
-	if Config == nil {
-		return LogConfig{
-			Level:      "info",
-			Encoding:   "json",
-			Filename:   "logs/app.log",
-			MaxSize:    100,
-			MaxAge:     7,
-			MaxBackups: 10,
-		}
+	cfg := Get()
+	if cfg == nil {
+		return Default().Logger
 	}
-	return Config.Logger
+	return cfg.Logger
 }
 
 // GetServerConfig 获取服务器配置
 func GetServerConfig() ServerConfig {
-	if Config == nil {
-		return ServerConfig{
-			Port: "8080",
-			Mode: "debug",
-		}
+	cfg := Get()
+	if cfg == nil {
+		return Default().Server
 	}
-	return Config.Server
+	return cfg.Server
 }
 
 // GetSwaggerConfig 获取Swagger配置
 func GetSwaggerConfig() SwaggerConfig {
-	if Config == nil {
-		return SwaggerConfig{
-			Title:       "HarborArk API",
-			Description: "HarborArk API Documentation",
-			Version:     "1.0.0",
-			Host:        "localhost:8080",
-			BasePath:    "/api/v1",
-			Enabled:     true,
-			AutoUpdate:  false,
-			OutputDir:   "cmd/docs",
-			MainApiFile: "cmd/server.go",
-			Schemes:     []string{"http", "https"},
-		}
+	cfg := Get()
+	if cfg == nil {
+		return Default().Swagger
+	}
+	return cfg.Swagger
+}
+
+// GetWebhookConfig 获取Webhook配置
+func GetWebhookConfig() WebhookConfig {
+	cfg := Get()
+	if cfg == nil {
+		return Default().Webhook
+	}
+	return cfg.Webhook
+}
+
+// GetAccessLogConfig 获取访问日志配置
+func GetAccessLogConfig() AccessLogConfig {
+	cfg := Get()
+	if cfg == nil {
+		return Default().AccessLog
+	}
+	return cfg.AccessLog
+}
+
+// GetAuditConfig 获取审计日志配置
+func GetAuditConfig() AuditConfig {
+	cfg := Get()
+	if cfg == nil {
+		return Default().Audit
+	}
+	return cfg.Audit
+}
+
+// GetMetricsConfig 获取指标配置
+func GetMetricsConfig() MetricsConfig {
+	cfg := Get()
+	if cfg == nil {
+		return Default().Metrics
+	}
+	return cfg.Metrics
+}
+
+// GetStorageConfig 获取存储配置
+func GetStorageConfig() StorageConfig {
+	cfg := Get()
+	if cfg == nil {
+		return Default().Storage
+	}
+	return cfg.Storage
+}
+
+// GetTracingConfig 获取链路追踪配置
+func GetTracingConfig() TracingConfig {
+	cfg := Get()
+	if cfg == nil {
+		return Default().Tracing
+	}
+	return cfg.Tracing
+}
+
+// GetHealthConfig 获取健康检查配置
+func GetHealthConfig() HealthConfig {
+	cfg := Get()
+	if cfg == nil {
+		return Default().Health
+	}
+	return cfg.Health
+}
+
+// GetSecretsConfig 获取密钥库配置
+func GetSecretsConfig() SecretsConfig {
+	cfg := Get()
+	if cfg == nil {
+		return Default().Secrets
+	}
+	return cfg.Secrets
+}
+
+// GetRateLimitConfig 获取限流配置
+func GetRateLimitConfig() RateLimitConfig {
+	cfg := Get()
+	if cfg == nil {
+		return Default().RateLimit
+	}
+	return cfg.RateLimit
+}
+
+// GetSecurityConfig 获取安全配置
+func GetSecurityConfig() SecurityConfig {
+	cfg := Get()
+	if cfg == nil {
+		return Default().Security
 	}
-	return Config.Swagger
+	return cfg.Security
 }
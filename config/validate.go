@@ -0,0 +1,420 @@
+package config
+
+import (
+	"HarborArk/internal/i18n"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/go-viper/mapstructure/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Problem 一个配置问题，Line 为 0 表示该项不在配置文件中（来自默认值、环境变量或命令行）
+type Problem struct {
+	File    string
+	Line    int
+	Key     string
+	Message string
+}
+
+// String 以 file:line: key: message 的形式输出
+func (p Problem) String() string {
+	var b strings.Builder
+	if p.File != "" {
+		b.WriteString(p.File)
+		if p.Line > 0 {
+			fmt.Fprintf(&b, ":%d", p.Line)
+		}
+		b.WriteString(": ")
+	}
+	b.WriteString(p.Key)
+	b.WriteString(": ")
+	b.WriteString(p.Message)
+	return b.String()
+}
+
+// ValidationError 汇总一次校验发现的全部问题
+type ValidationError struct {
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Problems)+1)
+	lines = append(lines, i18n.T("validation.config.failed", len(e.Problems)))
+	for _, p := range e.Problems {
+		lines = append(lines, "  "+p.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+var (
+	validateOnce sync.Once
+	validate     *validator.Validate
+)
+
+// getValidator 创建使用 mapstructure 键名的校验器
+func getValidator() *validator.Validate {
+	validateOnce.Do(func() {
+		validate = validator.New(validator.WithRequiredStructEnabled())
+		validate.RegisterTagNameFunc(func(f reflect.StructField) string {
+			return f.Tag.Get("mapstructure")
+		})
+		validate.RegisterValidation("port", func(fl validator.FieldLevel) bool {
+			n, err := strconv.Atoi(fl.Field().String())
+			return err == nil && n > 0 && n <= 65535
+		})
+		validate.RegisterValidation("origin", func(fl validator.FieldLevel) bool {
+			return validOrigin(fl.Field().String())
+		})
+		validate.RegisterStructValidation(validateTLS, TLSConfig{})
+		validate.RegisterStructValidation(validateCORS, CORSConfig{})
+		validate.RegisterStructValidation(validateACME, ACMEConfig{})
+	})
+	return validate
+}
+
+// validateTLS 校验 TLS 配置中相互依赖的字段
+func validateTLS(sl validator.StructLevel) {
+	cfg := sl.Current().Interface().(TLSConfig)
+	if !cfg.Enabled {
+		return
+	}
+	if !cfg.ACME.Enabled {
+		if cfg.CertFile == "" {
+			sl.ReportError(cfg.CertFile, "certFile", "CertFile", "required_without_acme", "")
+		}
+		if cfg.KeyFile == "" {
+			sl.ReportError(cfg.KeyFile, "keyFile", "KeyFile", "required_without_acme", "")
+		}
+	}
+	if (cfg.ClientAuth == "request" || cfg.ClientAuth == "require") && cfg.ClientCAFile == "" {
+		sl.ReportError(cfg.ClientCAFile, "clientCAFile", "ClientCAFile", "required_with_client_auth", "")
+	}
+}
+
+// validateACME 启用 ACME 时必须配置域名
+func validateACME(sl validator.StructLevel) {
+	cfg := sl.Current().Interface().(ACMEConfig)
+	if cfg.Enabled && len(cfg.Domains) == 0 {
+		sl.ReportError(cfg.Domains, "domains", "Domains", "required", "")
+	}
+}
+
+// validOrigin 判断是否为 * 或 scheme://host[:port] 形式的来源，host 可以 *. 开头
+func validOrigin(origin string) bool {
+	if origin == "*" {
+		return true
+	}
+	scheme, host, ok := strings.Cut(origin, "://")
+	if !ok || (scheme != "http" && scheme != "https") {
+		return false
+	}
+	host = strings.TrimPrefix(host, "*.")
+	u, err := url.Parse(scheme + "://" + host)
+	return err == nil && u.Host == host && u.Hostname() != "" && !strings.Contains(host, "*")
+}
+
+// validateCORS 携带凭据时不能允许任意来源
+func validateCORS(sl validator.StructLevel) {
+	cfg := sl.Current().Interface().(CORSConfig)
+	if cfg.AllowCredentials && slices.Contains(cfg.AllowOrigins, "*") {
+		sl.ReportError(cfg.AllowOrigins, "allowOrigins", "AllowOrigins", "no_wildcard_with_credentials", "")
+	}
+}
+
+// Validate 按字段上的 validate 标签校验配置，返回包含全部问题的 *ValidationError
+func (c *AppConfig) Validate() error {
+	err := getValidator().Struct(c)
+	if err == nil {
+		return nil
+	}
+	fieldErrs, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return err
+	}
+	problems := make([]Problem, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		key := fe.Namespace()
+		key = key[strings.Index(key, ".")+1:]
+		problems = append(problems, Problem{Key: key, Message: problemMessage(fe)})
+	}
+	return &ValidationError{Problems: problems}
+}
+
+// structRules 结构体级校验使用的标签
+var structRules = []string{"required_without_acme", "required_with_client_auth", "no_wildcard_with_credentials"}
+
+// ruleAliases 消息相同的标签共用一个消息键
+var ruleAliases = map[string]string{
+	"required_if": "required",
+	"min":         "gte",
+	"max":         "lte",
+	"cidr|ip":     "cidr_ip",
+}
+
+// ruleKey 返回校验标签对应的消息键
+func ruleKey(tag string) string {
+	if alias, ok := ruleAliases[tag]; ok {
+		tag = alias
+	}
+	return "validation.config.rule." + tag
+}
+
+// problemMessage 将校验失败的标签转换为说明
+func problemMessage(fe validator.FieldError) string {
+	param := fe.Param()
+	value := fmt.Sprint(fe.Value())
+	key := ruleKey(fe.Tag())
+	if _, ok := i18n.Lookup(i18n.Languages[0], key); !ok {
+		return i18n.T("validation.config.rule_failed", fe.Tag(), param)
+	}
+	switch strings.TrimPrefix(key, "validation.config.rule.") {
+	case "required", "required_without_acme", "required_with_client_auth", "no_wildcard_with_credentials":
+		return i18n.T(key)
+	case "oneof":
+		return i18n.T(key, value, strings.ReplaceAll(param, " ", ", "))
+	case "gt", "gte", "lte":
+		return i18n.T(key, param, value)
+	case "gtefield", "unique":
+		return i18n.T(key, lowerFirst(param))
+	case "startswith":
+		return i18n.T(key, param)
+	default:
+		return i18n.T(key, value)
+	}
+}
+
+// ValidateFile 校验 cfg 并检查配置文件中的未知项，问题按所在行排序并标注文件位置
+func ValidateFile(file string, cfg *AppConfig) error {
+	return checkFile(file, cfg, nil)
+}
+
+// checkFile 合并解析阶段与校验阶段的问题，已有解析问题的键不再重复报告
+func checkFile(file string, cfg *AppConfig, problems []Problem) error {
+	if err := cfg.Validate(); err != nil {
+		verr, ok := err.(*ValidationError)
+		if !ok {
+			return err
+		}
+		reported := make(map[string]bool, len(problems))
+		for _, p := range problems {
+			reported[strings.ToLower(p.Key)] = true
+		}
+		for _, p := range verr.Problems {
+			if !reported[strings.ToLower(p.Key)] {
+				problems = append(problems, p)
+			}
+		}
+	}
+
+	lines := map[string]int{}
+	if data, err := os.ReadFile(file); err == nil {
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return i18n.Errorf("validation.config.parse_file_failed", file, err)
+		}
+		if len(doc.Content) > 0 {
+			known := knownKeys("", reflect.TypeOf(AppConfig{}), map[string][]string{})
+			indexNode(doc.Content[0], "", lines)
+			problems = append(problems, unknownKeys(doc.Content[0], "", "", known)...)
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+
+	for i := range problems {
+		problems[i].File = file
+		if problems[i].Line == 0 {
+			problems[i].Line = lines[strings.ToLower(problems[i].Key)]
+		}
+		// 命令行与环境变量的优先级高于配置文件，标注实际来源
+		if flag := boundFlags[problems[i].Key]; flag != nil && flag.Changed {
+			problems[i].Message += i18n.T("validation.config.from_flag", flag.Name)
+		} else if env := envName(problems[i].Key); os.Getenv(env) != "" {
+			problems[i].Message += i18n.T("validation.config.from_env", env)
+		}
+	}
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Line < problems[j].Line
+	})
+	return &ValidationError{Problems: problems}
+}
+
+// decodeProblems 将 viper 解析阶段的类型错误转换为问题列表，无法识别的错误返回 nil
+func decodeProblems(err error) []Problem {
+	if multi, ok := err.(interface{ Unwrap() []error }); ok {
+		var problems []Problem
+		for _, e := range multi.Unwrap() {
+			problems = append(problems, decodeProblems(e)...)
+		}
+		return problems
+	}
+	var de *mapstructure.DecodeError
+	if !errors.As(err, &de) {
+		if inner := errors.Unwrap(err); inner != nil {
+			return decodeProblems(inner)
+		}
+		return nil
+	}
+	// 嵌套结构的错误会层层包装，取最内层的字段
+	if inner := de.Unwrap(); inner != nil {
+		if nested := decodeProblems(inner); len(nested) > 0 {
+			return nested
+		}
+	}
+
+	msg := de.Unwrap().Error()
+	var ute *mapstructure.UnconvertibleTypeError
+	var pe *mapstructure.ParseError
+	switch {
+	case errors.As(err, &ute):
+		msg = i18n.T("validation.config.type_mismatch", ute.Expected.Type(), ute.Value)
+	case errors.As(err, &pe):
+		msg = i18n.T("validation.config.parse_value_failed", pe.Expected.Type(), pe.Value)
+	}
+	return []Problem{{Key: de.Name(), Message: msg}}
+}
+
+// indexNode 记录每个配置键所在的行，键统一小写，序列元素使用 [i] 表示
+func indexNode(node *yaml.Node, path string, lines map[string]int) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := joinKey(path, strings.ToLower(node.Content[i].Value))
+			lines[key] = node.Content[i].Line
+			indexNode(node.Content[i+1], key, lines)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			key := fmt.Sprintf("%s[%d]", path, i)
+			lines[key] = item.Line
+			indexNode(item, key, lines)
+		}
+	}
+}
+
+// knownKeys 返回每一层允许出现的键，键为小写路径（序列元素用 []），值为该层的原始键名
+func knownKeys(path string, t reflect.Type, known map[string][]string) map[string][]string {
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Tag.Get("mapstructure")
+		if name == "" {
+			continue
+		}
+		known[path] = append(known[path], name)
+		ft := t.Field(i).Type
+		key := joinKey(path, strings.ToLower(name))
+		if ft.Kind() == reflect.Slice && ft.Elem().Kind() == reflect.Struct {
+			knownKeys(key+"[]", ft.Elem(), known)
+		} else if ft.Kind() == reflect.Struct && ft.PkgPath() == t.PkgPath() {
+			knownKeys(key, ft, known)
+		}
+	}
+	return known
+}
+
+// unknownKeys 找出配置文件中不对应任何配置项的键，键名比较不区分大小写（与 viper 一致）
+func unknownKeys(node *yaml.Node, path, display string, known map[string][]string) []Problem {
+	allowed, ok := known[path]
+	if !ok || node.Kind != yaml.MappingNode {
+		if node.Kind == yaml.SequenceNode {
+			if _, ok := known[path+"[]"]; ok {
+				var problems []Problem
+				for i, item := range node.Content {
+					problems = append(problems, unknownKeys(item, path+"[]", fmt.Sprintf("%s[%d]", display, i), known)...)
+				}
+				return problems
+			}
+		}
+		return nil
+	}
+
+	var problems []Problem
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		name := node.Content[i].Value
+		key := joinKey(path, strings.ToLower(name))
+		shown := joinKey(display, name)
+		if !containsFold(allowed, name) {
+			msg := i18n.T("validation.config.unknown_key")
+			if hint := closest(name, allowed); hint != "" {
+				msg += i18n.T("validation.config.did_you_mean", hint)
+			}
+			problems = append(problems, Problem{Line: node.Content[i].Line, Key: shown, Message: msg})
+			continue
+		}
+		problems = append(problems, unknownKeys(node.Content[i+1], key, shown, known)...)
+	}
+	return problems
+}
+
+// closest 返回与 name 最相近的合法键名，差异过大时返回空
+func closest(name string, candidates []string) string {
+	normalize := func(s string) string {
+		return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(s))
+	}
+	best, bestDist := "", 3
+	for _, c := range candidates {
+		if d := editDistance(normalize(name), normalize(c)); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// editDistance 计算编辑距离
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func joinKey(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// envName 返回配置键对应的环境变量名
+func envName(key string) string {
+	return envPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"HarborArk/internal/logging"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+// Subscriber 配置变更回调，prev 与 next 均为只读
+type Subscriber func(prev, next *AppConfig)
+
+var (
+	subMu       sync.Mutex
+	subscribers []Subscriber
+	reloadMu    sync.Mutex
+	// pending 配置文件中已修改但需重启才能生效的配置，没有时为 nil
+	pending atomic.Pointer[AppConfig]
+)
+
+// reloadDelay 合并编辑器保存时产生的多次写入事件，避免读到写了一半的文件
+const reloadDelay = 300 * time.Millisecond
+
+// restartSections 修改后需要重启才能生效的配置节
+var restartSections = []string{"server", "webhook", "audit", "metrics", "storage", "tracing", "health"}
+
+// hotFields 部分可热加载的配置节中能够热加载的字段，其余字段需要重启才能生效
+var hotFields = map[string][]string{
+	"logger":    {"level", "levels", "redact"},
+	"accessLog": {"format", "skip", "sampling"},
+}
+
+// Pending 返回配置文件中已修改但需重启才能生效的完整配置，没有时返回 nil。
+// 热加载后 Get 返回的配置中，这些配置项保持修改前的值，与运行中的服务一致
+func Pending() *AppConfig {
+	return pending.Load()
+}
+
+// Subscribe 注册配置变更回调，热加载成功后按注册顺序调用
+func Subscribe(fn Subscriber) {
+	subMu.Lock()
+	defer subMu.Unlock()
+	subscribers = append(subscribers, fn)
+}
+
+// Watch 监听配置文件，变化时重新解析并校验，校验失败时保留上一次有效的配置
+func Watch() {
+	var (
+		mu    sync.Mutex
+		timer *time.Timer
+	)
+	vp.OnConfigChange(func(e fsnotify.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		if timer != nil {
+			timer.Stop()
+		}
+		timer = time.AfterFunc(reloadDelay, reload)
+	})
+	vp.WatchConfig()
+}
+
+// reload 重新加载配置并通知订阅者
+func reload() {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	// viper 在回调前已尝试读取，这里再读一次以取得语法错误
+	if err := vp.ReadInConfig(); err != nil {
+		logging.Named("config").Error("配置文件读取失败，继续使用当前配置", zap.String("file", loadedFile), zap.Error(err))
+		return
+	}
+	next, refs, err := decode(vp, loadedFile)
+	if err != nil {
+		logging.Named("config").Error("配置无效，继续使用当前配置", zap.String("file", loadedFile), zap.Error(err))
+		return
+	}
+	currentRefs.Store(&refs)
+
+	// 需重启的配置项保持当前值，修改后的完整配置另外保存，供重启前查看
+	prev := current.Load()
+	applied := applyHot(prev, next)
+	restart := changedSections(applied, next)
+	var nextPending *AppConfig
+	if len(restart) > 0 {
+		nextPending = next
+	}
+	prevPending := pending.Swap(nextPending)
+	// 只比较需重启的配置项，上次已提示过的修改不再重复提示
+	pendingChanged := (prevPending == nil) != (nextPending == nil) ||
+		nextPending != nil && !reflect.DeepEqual(applyHot(prevPending, next), next)
+	if reflect.DeepEqual(prev, applied) && !pendingChanged {
+		return
+	}
+	current.Store(applied)
+
+	if pendingChanged {
+		for _, section := range restart {
+			logging.Named("config").Warn("配置已修改，需重启后生效", zap.String("section", section))
+		}
+	}
+	logging.Named("config").Info("配置已重新加载", zap.String("file", loadedFile))
+
+	subMu.Lock()
+	subs := append([]Subscriber(nil), subscribers...)
+	subMu.Unlock()
+	for _, fn := range subs {
+		fn(prev, applied)
+	}
+}
+
+// applyHot 返回热加载后生效的配置：需重启的配置节与 hotFields 之外的字段保持 prev 中的值，其余取 next 中的值
+func applyHot(prev, next *AppConfig) *AppConfig {
+	applied := *next
+	av, pv := reflect.ValueOf(&applied).Elem(), reflect.ValueOf(*prev)
+	t := av.Type()
+	for i := 0; i < t.NumField(); i++ {
+		key := t.Field(i).Tag.Get("mapstructure")
+		if slices.Contains(restartSections, key) {
+			av.Field(i).Set(pv.Field(i))
+			continue
+		}
+		fields, ok := hotFields[key]
+		if !ok {
+			continue
+		}
+		section := reflect.New(t.Field(i).Type).Elem()
+		section.Set(pv.Field(i))
+		st := section.Type()
+		for j := 0; j < st.NumField(); j++ {
+			if slices.Contains(fields, st.Field(j).Tag.Get("mapstructure")) {
+				section.Field(j).Set(av.Field(i).Field(j))
+			}
+		}
+		av.Field(i).Set(section)
+	}
+	return &applied
+}
+
+// changedSections 返回 applied 与配置文件中的配置不同，即需要重启才能生效的配置节
+func changedSections(applied, next *AppConfig) []string {
+	var changed []string
+	av, nv := reflect.ValueOf(*applied), reflect.ValueOf(*next)
+	t := av.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if !reflect.DeepEqual(av.Field(i).Interface(), nv.Field(i).Interface()) {
+			changed = append(changed, t.Field(i).Tag.Get("mapstructure"))
+		}
+	}
+	return changed
+}
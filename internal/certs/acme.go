@@ -0,0 +1,132 @@
+package certs
+
+import (
+	"HarborArk/config"
+	"HarborArk/internal/i18n"
+	"HarborArk/internal/logging"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// ACME 通过 ACME 协议自动申请与续期证书，支持 HTTP-01 与 TLS-ALPN-01 验证
+type ACME struct {
+	manager *autocert.Manager
+	domains []string
+}
+
+// NewACME 创建 ACME 证书管理器，证书缓存在 cfg.CacheDir，到期前 cfg.RenewBefore 自动续期
+func NewACME(cfg config.ACMEConfig) (*ACME, error) {
+	if len(cfg.Domains) == 0 {
+		return nil, i18n.NewError("errors.certs.acme_domains_required")
+	}
+
+	httpClient := http.DefaultClient
+	if cfg.DirectoryCAFile != "" {
+		pemData, err := os.ReadFile(cfg.DirectoryCAFile)
+		if err != nil {
+			return nil, i18n.NewError("errors.certs.acme_read_ca_failed", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pemData) {
+			return nil, i18n.NewError("errors.certs.acme_no_ca", cfg.DirectoryCAFile)
+		}
+		httpClient = &http.Client{
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{RootCAs: pool},
+			},
+		}
+	}
+
+	manager := &autocert.Manager{
+		Prompt:      autocert.AcceptTOS,
+		Cache:       autocert.DirCache(cfg.CacheDir),
+		HostPolicy:  autocert.HostWhitelist(cfg.Domains...),
+		RenewBefore: cfg.RenewBefore,
+		Email:       cfg.Email,
+		Client: &acme.Client{
+			DirectoryURL: cfg.DirectoryURL,
+			HTTPClient:   httpClient,
+		},
+	}
+	return &ACME{manager: manager, domains: cfg.Domains}, nil
+}
+
+// GetCertificate 供 tls.Config.GetCertificate 使用，同时响应 TLS-ALPN-01 验证
+func (a *ACME) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return a.manager.GetCertificate(hello)
+}
+
+// HTTPHandler 响应 HTTP-01 验证请求，其余请求交给 fallback
+func (a *ACME) HTTPHandler(fallback http.Handler) http.Handler {
+	return a.manager.HTTPHandler(fallback)
+}
+
+// Prefetch 在后台为全部域名预先申请证书，同时启动续期计划，避免首个请求等待签发
+func (a *ACME) Prefetch() {
+	for _, domain := range a.domains {
+		go func(domain string) {
+			hello := &tls.ClientHelloInfo{
+				ServerName:        domain,
+				SignatureSchemes:  []tls.SignatureScheme{tls.ECDSAWithP256AndSHA256},
+				SupportedCurves:   []tls.CurveID{tls.CurveP256},
+				SupportedVersions: []uint16{tls.VersionTLS13},
+				CipherSuites:      []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256},
+			}
+			if _, err := a.manager.GetCertificate(hello); err != nil {
+				logging.Named("certs").Error("ACME 证书申请失败", zap.String("domain", domain), zap.Error(err))
+				return
+			}
+			logging.Named("certs").Info("ACME 证书已就绪", zap.String("domain", domain))
+		}(domain)
+	}
+}
+
+// Expiry 从证书缓存读取各域名证书的到期时间，不会触发签发
+func (a *ACME) Expiry(ctx context.Context) (map[string]time.Time, error) {
+	expiry := make(map[string]time.Time, len(a.domains))
+	for _, domain := range a.domains {
+		data, err := a.manager.Cache.Get(ctx, domain)
+		if errors.Is(err, autocert.ErrCacheMiss) {
+			return nil, i18n.NewError("api.health.acme_not_issued", domain)
+		}
+		if err != nil {
+			return nil, err
+		}
+		leaf, err := leafFromPEM(data)
+		if err != nil {
+			return nil, i18n.NewError("api.health.acme_parse_failed", domain, err)
+		}
+		expiry[domain] = leaf.NotAfter
+	}
+	return expiry, nil
+}
+
+// Close 实现 Source
+func (a *ACME) Close() error {
+	return nil
+}
+
+// leafFromPEM 从 autocert 缓存内容（私钥 + 证书链）中解析叶子证书
+func leafFromPEM(data []byte) (*x509.Certificate, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, i18n.NewError("errors.certs.no_certificate")
+		}
+		if block.Type == "CERTIFICATE" {
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+}
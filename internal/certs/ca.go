@@ -0,0 +1,199 @@
+package certs
+
+import (
+	"HarborArk/internal/i18n"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// CA 本地证书颁发机构，用于没有公网域名的局域网部署
+type CA struct {
+	Cert *x509.Certificate
+	Key  crypto.Signer
+}
+
+// NewCA 生成自签名根证书，有效期为 validity
+func NewCA(commonName string, validity time.Duration) (*CA, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, i18n.NewError("errors.certs.ca_key_failed", err)
+	}
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: commonName, Organization: []string{"HarborArk"}},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(validity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, i18n.NewError("errors.certs.ca_sign_failed", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &CA{Cert: cert, Key: key}, nil
+}
+
+// LoadCA 从 PEM 文件加载 CA 证书与私钥
+func LoadCA(certFile, keyFile string) (*CA, error) {
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, i18n.NewError("errors.certs.read_ca_failed", err)
+	}
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, i18n.NewError("errors.certs.invalid_ca_pem", certFile)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, i18n.NewError("errors.certs.parse_ca_failed", err)
+	}
+	if !cert.IsCA {
+		return nil, i18n.NewError("errors.certs.not_ca", certFile)
+	}
+
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, i18n.NewError("errors.certs.read_ca_key_failed", err)
+	}
+	block, _ = pem.Decode(keyPEM)
+	if block == nil {
+		return nil, i18n.NewError("errors.certs.invalid_ca_key_pem", keyFile)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, i18n.NewError("errors.certs.parse_ca_key_failed", err)
+	}
+	key, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, i18n.NewError("errors.certs.unsupported_ca_key")
+	}
+	return &CA{Cert: cert, Key: key}, nil
+}
+
+// Issue 为 hosts 签发服务端证书，hosts 可以是域名或 IP，第一个作为 CommonName
+func (ca *CA) Issue(hosts []string, validity time.Duration) (certPEM, keyPEM []byte, err error) {
+	if len(hosts) == 0 {
+		return nil, nil, i18n.NewError("errors.certs.hosts_required")
+	}
+	template := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: hosts[0], Organization: []string{"HarborArk"}},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(strings.Trim(host, "[]")); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+	return ca.sign(template, validity)
+}
+
+// IssueClient 签发用于 mTLS 认证的客户端证书，commonName 对应配置中 clientUsers 的 commonName
+func (ca *CA) IssueClient(commonName string, validity time.Duration) (certPEM, keyPEM []byte, err error) {
+	if commonName == "" {
+		return nil, nil, i18n.NewError("errors.certs.common_name_required")
+	}
+	return ca.sign(&x509.Certificate{
+		Subject:     pkix.Name{CommonName: commonName, Organization: []string{"HarborArk"}},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, validity)
+}
+
+// sign 生成私钥并用 CA 签发证书，有效期不超过 CA 证书
+func (ca *CA) sign(template *x509.Certificate, validity time.Duration) (certPEM, keyPEM []byte, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, i18n.NewError("errors.certs.key_failed", err)
+	}
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	notAfter := now.Add(validity)
+	if notAfter.After(ca.Cert.NotAfter) {
+		notAfter = ca.Cert.NotAfter
+	}
+	template.SerialNumber = serial
+	template.NotBefore = now.Add(-time.Hour)
+	template.NotAfter = notAfter
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.Cert, key.Public(), ca.Key)
+	if err != nil {
+		return nil, nil, i18n.NewError("errors.certs.sign_failed", err)
+	}
+	keyPEM, err = encodeKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	// 证书链中附带 CA 证书，客户端只需信任根证书即可
+	certPEM = append(EncodeCert(der), EncodeCert(ca.Cert.Raw)...)
+	return certPEM, keyPEM, nil
+}
+
+// CertPEM 返回 PEM 编码的 CA 证书
+func (ca *CA) CertPEM() []byte {
+	return EncodeCert(ca.Cert.Raw)
+}
+
+// KeyPEM 返回 PEM 编码的 CA 私钥
+func (ca *CA) KeyPEM() ([]byte, error) {
+	return encodeKey(ca.Key)
+}
+
+// Fingerprint 返回 CA 证书的 SHA-256 指纹，便于在客户端核对
+func (ca *CA) Fingerprint() string {
+	sum := sha256.Sum256(ca.Cert.Raw)
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, ":")
+}
+
+// EncodeCert 将 DER 证书编码为 PEM
+func EncodeCert(der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func encodeKey(key crypto.Signer) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, i18n.NewError("errors.certs.encode_key_failed", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+func serialNumber() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, i18n.NewError("errors.certs.serial_failed", err)
+	}
+	return serial, nil
+}
@@ -0,0 +1,136 @@
+package certs
+
+import (
+	"HarborArk/internal/i18n"
+	"HarborArk/internal/logging"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+// reloadDelay 合并短时间内的多次文件事件，等待证书与私钥都写入完成
+const reloadDelay = 500 * time.Millisecond
+
+// Reloader 从文件加载证书，并在文件变化时自动重新加载
+type Reloader struct {
+	certFile string
+	keyFile  string
+	cert     atomic.Pointer[tls.Certificate]
+	watcher  *fsnotify.Watcher
+}
+
+// NewReloader 加载证书与私钥
+func NewReloader(certFile, keyFile string) (*Reloader, error) {
+	r := &Reloader{certFile: certFile, keyFile: keyFile}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// load 读取证书与私钥，失败时保留上一次成功加载的证书
+func (r *Reloader) load() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return i18n.NewError("errors.certs.load_failed", err)
+	}
+	if cert.Leaf == nil && len(cert.Certificate) > 0 {
+		if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+			return i18n.NewError("errors.certs.parse_failed", err)
+		}
+	}
+	r.cert.Store(&cert)
+	return nil
+}
+
+// Watch 监听证书所在目录。监听目录而非文件本身，以便兼容先写临时文件再重命名的替换方式
+func (r *Reloader) Watch() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	dirs := map[string]bool{
+		filepath.Dir(r.certFile): true,
+		filepath.Dir(r.keyFile):  true,
+	}
+	for dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			watcher.Close()
+			return i18n.NewError("errors.certs.watch_failed", err)
+		}
+	}
+	r.watcher = watcher
+	go r.loop()
+	return nil
+}
+
+// Close 停止监听
+func (r *Reloader) Close() error {
+	if r.watcher == nil {
+		return nil
+	}
+	return r.watcher.Close()
+}
+
+func (r *Reloader) loop() {
+	certFile, _ := filepath.Abs(r.certFile)
+	keyFile, _ := filepath.Abs(r.keyFile)
+
+	var timer *time.Timer
+	for {
+		select {
+		case event, ok := <-r.watcher.Events:
+			if !ok {
+				return
+			}
+			name, _ := filepath.Abs(event.Name)
+			if name != certFile && name != keyFile {
+				continue
+			}
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = time.AfterFunc(reloadDelay, r.reload)
+		case err, ok := <-r.watcher.Errors:
+			if !ok {
+				return
+			}
+			logging.Named("certs").Warn("监听证书文件出错", zap.Error(err))
+		}
+	}
+}
+
+func (r *Reloader) reload() {
+	if err := r.load(); err != nil {
+		logging.Named("certs").Error("证书热加载失败，继续使用旧证书", zap.Error(err))
+		return
+	}
+	cert := r.Certificate()
+	logging.Named("certs").Info("证书已重新加载",
+		zap.String("subject", cert.Leaf.Subject.String()),
+		zap.Time("notAfter", cert.Leaf.NotAfter),
+	)
+}
+
+// Certificate 获取当前证书
+func (r *Reloader) Certificate() *tls.Certificate {
+	return r.cert.Load()
+}
+
+// Expiry 实现 Source，返回当前证书的到期时间
+func (r *Reloader) Expiry(ctx context.Context) (map[string]time.Time, error) {
+	return map[string]time.Time{
+		r.certFile: r.Certificate().Leaf.NotAfter,
+	}, nil
+}
+
+// GetCertificate 供 tls.Config.GetCertificate 使用
+func (r *Reloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.cert.Load(), nil
+}
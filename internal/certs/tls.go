@@ -0,0 +1,121 @@
+package certs
+
+import (
+	"HarborArk/config"
+	"HarborArk/internal/i18n"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Source 服务端证书来源
+type Source interface {
+	// GetCertificate 供 tls.Config.GetCertificate 使用
+	GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error)
+	// Expiry 返回各证书的到期时间，用于健康检查
+	Expiry(ctx context.Context) (map[string]time.Time, error)
+	Close() error
+}
+
+// tlsVersions 支持配置的最低 TLS 版本
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// clientAuthTypes 客户端证书校验方式
+var clientAuthTypes = map[string]tls.ClientAuthType{
+	"":        tls.NoClientCert,
+	"none":    tls.NoClientCert,
+	"request": tls.VerifyClientCertIfGiven,
+	"require": tls.RequireAndVerifyClientCert,
+}
+
+// ServerTLSConfig 根据配置构建服务端 tls.Config，证书通过 getCertificate 动态获取
+func ServerTLSConfig(cfg config.TLSConfig, getCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		GetCertificate: getCertificate,
+		NextProtos:     []string{"h2", "http/1.1"},
+	}
+
+	minVersion := cfg.MinVersion
+	if minVersion == "" {
+		minVersion = "1.2"
+	}
+	version, ok := tlsVersions[minVersion]
+	if !ok {
+		return nil, i18n.NewError("errors.certs.unsupported_min_version", cfg.MinVersion)
+	}
+	tlsConfig.MinVersion = version
+
+	if len(cfg.CipherSuites) > 0 {
+		suites, err := cipherSuiteIDs(cfg.CipherSuites)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.CipherSuites = suites
+	}
+
+	clientAuth, ok := clientAuthTypes[cfg.ClientAuth]
+	if !ok {
+		return nil, i18n.NewError("errors.certs.unsupported_client_auth", cfg.ClientAuth)
+	}
+	tlsConfig.ClientAuth = clientAuth
+	if clientAuth != tls.NoClientCert {
+		if cfg.ClientCAFile == "" {
+			return nil, i18n.NewError("errors.certs.client_ca_required")
+		}
+		pem, err := os.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, i18n.NewError("errors.certs.read_client_ca_failed", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, i18n.NewError("errors.certs.no_client_ca", cfg.ClientCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+	}
+
+	return tlsConfig, nil
+}
+
+// cipherSuiteIDs 将套件名称转换为 ID，只允许 Go 认为安全的套件
+func cipherSuiteIDs(names []string) ([]uint16, error) {
+	known := make(map[string]uint16)
+	for _, suite := range tls.CipherSuites() {
+		known[suite.Name] = suite.ID
+	}
+	ids := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := known[name]
+		if !ok {
+			return nil, i18n.NewError("errors.certs.insecure_cipher", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// RedirectHandler 将 HTTP 请求永久重定向到 httpsPort 上的 HTTPS 地址
+func RedirectHandler(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		} else {
+			host = strings.Trim(host, "[]")
+		}
+		if httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		} else if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+		target := "https://" + host + r.URL.RequestURI()
+		http.Redirect(w, r, target, http.StatusPermanentRedirect)
+	})
+}
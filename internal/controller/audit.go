@@ -0,0 +1,184 @@
+package controller
+
+import (
+	"HarborArk/internal/response"
+	"HarborArk/internal/service/audit"
+	"HarborArk/router/middleware"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// recordAudit 以当前请求的用户与来源 IP 写入审计记录
+func recordAudit(c *gin.Context, action, resource, result string, detail map[string]string) {
+	actor := middleware.CurrentUser(c)
+	if actor == "" {
+		actor = "anonymous"
+	}
+	audit.Record(c.Request.Context(), audit.Entry{
+		Actor:    actor,
+		IP:       c.ClientIP(),
+		Action:   action,
+		Resource: resource,
+		Result:   result,
+		Detail:   detail,
+	})
+}
+
+// auditLogger 获取审计日志，未启用时返回 503
+func auditLogger(c *gin.Context) *audit.Logger {
+	l := audit.Default()
+	if l == nil {
+		response.Fail(c, response.Unavailable(tr(c, "api.audit.disabled")))
+	}
+	return l
+}
+
+// parseAuditFilter 解析审计查询参数
+func parseAuditFilter(c *gin.Context) (audit.Filter, bool) {
+	f := audit.Filter{
+		Actor:    c.Query("actor"),
+		Action:   c.Query("action"),
+		Resource: c.Query("resource"),
+		Result:   c.Query("result"),
+	}
+
+	var err error
+	if v := c.Query("since"); v != "" {
+		if f.Since, err = time.Parse(time.RFC3339, v); err != nil {
+			response.Fail(c, response.BadRequest(tr(c, "api.audit.invalid_since")))
+			return f, false
+		}
+	}
+	if v := c.Query("until"); v != "" {
+		if f.Until, err = time.Parse(time.RFC3339, v); err != nil {
+			response.Fail(c, response.BadRequest(tr(c, "api.audit.invalid_until")))
+			return f, false
+		}
+	}
+	return f, true
+}
+
+// GetAuditLogs 查询审计日志
+// @Summary 查询审计日志
+// @Description 按条件分页查询安全相关操作的审计记录，按时间倒序返回
+// @Tags 审计日志
+// @Produce json
+// @Param actor query string false "操作人"
+// @Param action query string false "动作，支持前缀匹配，如 auth."
+// @Param resource query string false "资源，支持前缀匹配"
+// @Param result query string false "结果" Enums(success, failure)
+// @Param since query string false "起始时间 (RFC3339)"
+// @Param until query string false "结束时间 (RFC3339)"
+// @Param limit query int false "每页数量" default(50)
+// @Param offset query int false "偏移量" default(0)
+// @Success 200 {object} response.PageBody{data=[]audit.Entry}
+// @Failure 400 {object} response.ErrorBody
+// @Failure 503 {object} response.ErrorBody
+// @Router /audit [get]
+func GetAuditLogs(c *gin.Context) {
+	l := auditLogger(c)
+	if l == nil {
+		return
+	}
+	f, ok := parseAuditFilter(c)
+	if !ok {
+		return
+	}
+	f.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "50"))
+	f.Offset, _ = strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if f.Limit <= 0 || f.Limit > 1000 {
+		f.Limit = 50
+	}
+
+	entries, total, err := l.Query(c.Request.Context(), f)
+	if err != nil {
+		response.Fail(c, response.Internal(tr(c, "api.audit.read_failed")).WithCause(err))
+		return
+	}
+	response.Page(c, entries, total, tr(c, "api.common.fetched"))
+}
+
+// ExportAuditLogs 导出审计日志
+// @Summary 导出审计日志
+// @Description 按条件导出全部匹配的审计记录，支持 CSV 与 JSON 格式。以附件形式下载，JSON 格式为记录数组，不使用统一响应结构
+// @Tags 审计日志
+// @Produce json
+// @Produce text/csv
+// @Param format query string false "导出格式" Enums(json, csv) default(json)
+// @Param actor query string false "操作人"
+// @Param action query string false "动作，支持前缀匹配"
+// @Param resource query string false "资源，支持前缀匹配"
+// @Param result query string false "结果" Enums(success, failure)
+// @Param since query string false "起始时间 (RFC3339)"
+// @Param until query string false "结束时间 (RFC3339)"
+// @Success 200 {array} audit.Entry
+// @Failure 400 {object} response.ErrorBody
+// @Failure 503 {object} response.ErrorBody
+// @Router /audit/export [get]
+func ExportAuditLogs(c *gin.Context) {
+	l := auditLogger(c)
+	if l == nil {
+		return
+	}
+	f, ok := parseAuditFilter(c)
+	if !ok {
+		return
+	}
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		response.Fail(c, response.BadRequest(tr(c, "api.audit.invalid_format")))
+		return
+	}
+
+	entries, _, err := l.Query(c.Request.Context(), f)
+	if err != nil {
+		response.Fail(c, response.Internal(tr(c, "api.audit.read_failed")).WithCause(err))
+		return
+	}
+
+	filename := "audit-" + time.Now().Format("20060102-150405") + "." + format
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+	if format == "csv" {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		err = audit.WriteCSV(c.Writer, entries)
+	} else {
+		c.Header("Content-Type", "application/json; charset=utf-8")
+		err = audit.WriteJSON(c.Writer, entries)
+	}
+	if err != nil {
+		c.Error(err) // nolint: errcheck
+	}
+}
+
+// AuditVerifyResult 审计日志校验结果
+type AuditVerifyResult struct {
+	// Verified 校验通过的条数，校验失败时为出错位置之前的条数
+	Verified int64 `json:"verified" example:"1024"`
+}
+
+// VerifyAuditLog 校验审计日志完整性
+// @Summary 校验审计日志完整性
+// @Description 重新计算哈希链，检查审计日志是否被篡改
+// @Tags 审计日志
+// @Produce json
+// @Success 200 {object} response.Body{data=AuditVerifyResult}
+// @Failure 409 {object} response.ErrorBody{data=AuditVerifyResult}
+// @Failure 503 {object} response.ErrorBody
+// @Router /audit/verify [get]
+func VerifyAuditLog(c *gin.Context) {
+	l := auditLogger(c)
+	if l == nil {
+		return
+	}
+	count, err := l.Verify()
+	result := AuditVerifyResult{Verified: count}
+	if err != nil {
+		response.Fail(c, response.Conflict(tr(c, "api.audit.verify_failed")).WithDetail(errDetail(c, err)).WithData(result))
+		return
+	}
+	response.OK(c, result, tr(c, "api.audit.verified"))
+}
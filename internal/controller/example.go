@@ -1,7 +1,10 @@
 package controller
 
 import (
-	"net/http"
+	"HarborArk/internal/response"
+	"HarborArk/internal/service/audit"
+	"HarborArk/internal/service/webhook"
+	"HarborArk/internal/validation"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -9,9 +12,9 @@ import (
 
 // User 用户结构体
 type User struct {
-	ID   int    `json:"id" example:"1"`
-	Name string `json:"name" example:"张三"`
-	Age  int    `json:"age" example:"25"`
+	ID   int    `json:"id" readonly:"true" example:"1"`
+	Name string `json:"name" binding:"required,max=32" example:"张三"`
+	Age  int    `json:"age" binding:"gte=0,lte=150" example:"25"`
 }
 
 // GetUsers 获取用户列表
@@ -20,18 +23,14 @@ type User struct {
 // @Tags 用户管理
 // @Accept json
 // @Produce json
-// @Success 200 {array} User
+// @Success 200 {object} response.Body{data=[]User}
 // @Router /users [get]
 func GetUsers(c *gin.Context) {
 	users := []User{
 		{ID: 1, Name: "张三", Age: 25},
 		{ID: 2, Name: "李四", Age: 30},
 	}
-	c.JSON(http.StatusOK, gin.H{
-		"code":    200,
-		"data":    users,
-		"message": "获取成功",
-	})
+	response.OK(c, users, tr(c, "api.common.fetched"))
 }
 
 // GetUser 根据ID获取用户
@@ -41,35 +40,24 @@ func GetUsers(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "用户ID"
-// @Success 200 {object} User
-// @Failure 400 {object} map[string]interface{}
-// @Failure 404 {object} map[string]interface{}
+// @Success 200 {object} response.Body{data=User}
+// @Failure 400 {object} response.ErrorBody
+// @Failure 404 {object} response.ErrorBody
 // @Router /users/{id} [get]
 func GetUser(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code":    400,
-			"message": "无效的用户ID",
-		})
+		response.Fail(c, response.BadRequest(tr(c, "api.user.invalid_id")).WithDetail(errDetail(c, err)))
 		return
 	}
 
 	// 模拟数据
-	if id == 1 {
-		user := User{ID: 1, Name: "张三", Age: 25}
-		c.JSON(http.StatusOK, gin.H{
-			"code":    200,
-			"data":    user,
-			"message": "获取成功",
-		})
-	} else {
-		c.JSON(http.StatusNotFound, gin.H{
-			"code":    404,
-			"message": "用户不存在",
-		})
+	if id != 1 {
+		response.Fail(c, response.NotFound(tr(c, "api.user.not_found")))
+		return
 	}
+	response.OK(c, User{ID: 1, Name: "张三", Age: 25}, tr(c, "api.common.fetched"))
 }
 
 // CreateUser 创建用户
@@ -79,25 +67,20 @@ func GetUser(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param user body User true "用户信息"
-// @Success 201 {object} User
-// @Failure 400 {object} map[string]interface{}
+// @Success 201 {object} response.Body{data=User}
+// @Failure 400 {object} response.ErrorBody{data=[]validation.FieldError}
 // @Router /users [post]
 func CreateUser(c *gin.Context) {
 	var user User
-	if err := c.ShouldBindJSON(&user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"code":    400,
-			"message": "请求参数错误",
-			"error":   err.Error(),
-		})
+	if !validation.BindJSON(c, &user) {
 		return
 	}
 
 	// 模拟创建用户
 	user.ID = 3
-	c.JSON(http.StatusCreated, gin.H{
-		"code":    201,
-		"data":    user,
-		"message": "创建成功",
+	recordAudit(c, audit.ActionUserCreate, "users/"+strconv.Itoa(user.ID), audit.ResultSuccess, map[string]string{
+		"name": user.Name,
 	})
+	webhook.Publish(c.Request.Context(), webhook.EventUserCreated, user)
+	response.Created(c, user, tr(c, "api.common.created"))
 }
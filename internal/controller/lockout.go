@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"HarborArk/internal/ratelimit"
+	"HarborArk/internal/response"
+	"HarborArk/internal/service/audit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetLockouts 获取登录失败锁定
+// @Summary 获取登录失败锁定
+// @Description 返回因连续登录失败而处于锁定状态的用户与客户端 IP
+// @Tags Lockouts
+// @Produce json
+// @Param prefix query string false "只返回以此开头的键，如 user: 或 ip:"
+// @Success 200 {object} response.Body{data=[]ratelimit.LockedKey}
+// @Router /admin/lockouts [get]
+func GetLockouts(c *gin.Context) {
+	response.OK(c, ratelimit.Lockouts().List(c.Query("prefix")), tr(c, "api.common.fetched"))
+}
+
+// DeleteLockout 解除登录失败锁定
+// @Summary 解除登录失败锁定
+// @Description 解除锁定并清除失败次数，下一次锁定重新从最短时长开始
+// @Tags Lockouts
+// @Produce json
+// @Param key query string true "锁定的键，如 user:alice、ip:203.0.113.7"
+// @Success 200 {object} response.Body
+// @Failure 400 {object} response.ErrorBody
+// @Failure 404 {object} response.ErrorBody
+// @Router /admin/lockouts [delete]
+func DeleteLockout(c *gin.Context) {
+	key := c.Query("key")
+	if key == "" {
+		response.Fail(c, response.BadRequest(tr(c, "api.lockout.key_required")))
+		return
+	}
+	if !ratelimit.Lockouts().Unlock(key) {
+		response.Fail(c, response.NotFound(tr(c, "api.lockout.not_found", key)))
+		return
+	}
+	recordAudit(c, audit.ActionUnlock, key, audit.ResultSuccess, nil)
+	response.OK(c, nil, tr(c, "api.lockout.unlocked", key))
+}
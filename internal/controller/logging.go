@@ -0,0 +1,181 @@
+package controller
+
+import (
+	"HarborArk/internal/logging"
+	"HarborArk/internal/response"
+	"HarborArk/internal/service/audit"
+	"HarborArk/internal/validation"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LogLevelRequest 修改日志级别请求
+type LogLevelRequest struct {
+	Level string `json:"level" binding:"required" enums:"debug,info,warn,error,dpanic,panic,fatal" example:"debug"`
+}
+
+// DebugRuleRequest 临时调试规则请求，用户与路径至少指定一项
+type DebugRuleRequest struct {
+	User     string `json:"user" example:"alice"`
+	Path     string `json:"path" example:"/api/v1/users"`
+	Duration string `json:"duration" example:"10m"`
+}
+
+// LoggingState 日志级别状态
+type LoggingState struct {
+	Level   string              `json:"level" example:"info"`
+	Loggers map[string]string   `json:"loggers"`
+	Debug   []logging.DebugRule `json:"debug"`
+}
+
+// loggingState 返回当前的日志级别状态
+func loggingState() LoggingState {
+	return LoggingState{
+		Level:   logging.Level().String(),
+		Loggers: logging.LoggerLevels(),
+		Debug:   logging.DebugRules(),
+	}
+}
+
+// GetLogging 获取日志级别
+// @Summary 获取日志级别
+// @Description 返回全局日志级别、单独设置了级别的组件以及临时调试规则
+// @Tags Logging
+// @Produce json
+// @Success 200 {object} response.Body{data=LoggingState}
+// @Router /admin/logging [get]
+func GetLogging(c *gin.Context) {
+	response.OK(c, loggingState(), tr(c, "api.common.fetched"))
+}
+
+// SetLogLevel 修改全局日志级别
+// @Summary 修改全局日志级别
+// @Description 立即生效，服务重启或配置文件中的 logger.level 变化后以配置为准
+// @Tags Logging
+// @Accept json
+// @Produce json
+// @Param level body LogLevelRequest true "日志级别"
+// @Success 200 {object} response.Body{data=LoggingState}
+// @Failure 400 {object} response.ErrorBody{data=[]validation.FieldError}
+// @Router /admin/logging/level [put]
+func SetLogLevel(c *gin.Context) {
+	var req LogLevelRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+	from := logging.Level().String()
+	if err := logging.SetLevel(req.Level); err != nil {
+		response.Fail(c, response.BadRequest(tr(c, "api.logging.invalid_level")).WithDetail(errDetail(c, err)))
+		return
+	}
+	recordAudit(c, audit.ActionConfigChange, "logging/level", audit.ResultSuccess, map[string]string{
+		"from": from,
+		"to":   req.Level,
+	})
+	response.OK(c, loggingState(), tr(c, "api.common.updated"))
+}
+
+// SetLoggerLevel 设置组件日志级别
+// @Summary 设置组件日志级别
+// @Description 为指定组件及其子组件单独设置日志级别，如 webhook、audit、certs、config、server
+// @Tags Logging
+// @Accept json
+// @Produce json
+// @Param name path string true "组件名称"
+// @Param level body LogLevelRequest true "日志级别"
+// @Success 200 {object} response.Body{data=LoggingState}
+// @Failure 400 {object} response.ErrorBody{data=[]validation.FieldError}
+// @Router /admin/logging/loggers/{name} [put]
+func SetLoggerLevel(c *gin.Context) {
+	var req LogLevelRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+	name := c.Param("name")
+	if err := logging.SetLoggerLevel(name, req.Level); err != nil {
+		response.Fail(c, response.BadRequest(tr(c, "api.logging.invalid_level")).WithDetail(errDetail(c, err)))
+		return
+	}
+	recordAudit(c, audit.ActionConfigChange, "logging/loggers/"+name, audit.ResultSuccess, map[string]string{
+		"level": req.Level,
+	})
+	response.OK(c, loggingState(), tr(c, "api.common.updated"))
+}
+
+// ResetLoggerLevel 恢复组件日志级别
+// @Summary 恢复组件日志级别
+// @Description 取消组件单独设置的级别，恢复使用全局级别
+// @Tags Logging
+// @Produce json
+// @Param name path string true "组件名称"
+// @Success 200 {object} response.Body{data=LoggingState}
+// @Failure 404 {object} response.ErrorBody
+// @Router /admin/logging/loggers/{name} [delete]
+func ResetLoggerLevel(c *gin.Context) {
+	name := c.Param("name")
+	if !logging.ResetLoggerLevel(name) {
+		response.Fail(c, response.NotFound(tr(c, "api.logging.logger_not_set")))
+		return
+	}
+	recordAudit(c, audit.ActionConfigChange, "logging/loggers/"+name, audit.ResultSuccess, map[string]string{
+		"level": "reset",
+	})
+	response.OK(c, loggingState(), tr(c, "api.logging.reset"))
+}
+
+// CreateDebugRule 临时启用调试日志
+// @Summary 临时启用调试日志
+// @Description 为指定用户或路径前缀的请求输出调试日志，到期后自动恢复，默认 10 分钟，最长 24 小时
+// @Tags Logging
+// @Accept json
+// @Produce json
+// @Param rule body DebugRuleRequest true "调试规则"
+// @Success 201 {object} response.Body{data=logging.DebugRule}
+// @Failure 400 {object} response.ErrorBody{data=[]validation.FieldError}
+// @Router /admin/logging/debug [post]
+func CreateDebugRule(c *gin.Context) {
+	var req DebugRuleRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+	duration := 10 * time.Minute
+	if req.Duration != "" {
+		d, err := time.ParseDuration(req.Duration)
+		if err != nil {
+			response.Fail(c, response.BadRequest(tr(c, "api.logging.invalid_duration")).WithDetail(errDetail(c, err)))
+			return
+		}
+		duration = d
+	}
+	rule, err := logging.AddDebugRule(req.User, req.Path, duration)
+	if err != nil {
+		response.Fail(c, response.BadRequest(tr(c, "api.logging.invalid_debug_rule")).WithDetail(errDetail(c, err)))
+		return
+	}
+	recordAudit(c, audit.ActionConfigChange, "logging/debug/"+rule.ID, audit.ResultSuccess, map[string]string{
+		"user":      rule.User,
+		"path":      rule.Path,
+		"expiresAt": rule.ExpiresAt.Format(time.RFC3339),
+	})
+	response.Created(c, rule, tr(c, "api.common.created"))
+}
+
+// DeleteDebugRule 取消临时调试
+// @Summary 取消临时调试
+// @Description 立即删除临时调试规则
+// @Tags Logging
+// @Produce json
+// @Param id path string true "规则 ID"
+// @Success 200 {object} response.Body
+// @Failure 404 {object} response.ErrorBody
+// @Router /admin/logging/debug/{id} [delete]
+func DeleteDebugRule(c *gin.Context) {
+	id := c.Param("id")
+	if !logging.RemoveDebugRule(id) {
+		response.Fail(c, response.NotFound(tr(c, "api.logging.debug_rule_not_found")))
+		return
+	}
+	recordAudit(c, audit.ActionConfigChange, "logging/debug/"+id, audit.ResultSuccess, nil)
+	response.OK(c, nil, tr(c, "api.common.deleted"))
+}
@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"HarborArk/config"
+	"HarborArk/internal/response"
+	"HarborArk/internal/service/logs"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// logKeepAlive SSE 心跳间隔，避免反向代理断开空闲连接
+const logKeepAlive = 15 * time.Second
+
+// parseLogFilter 解析日志查询参数
+func parseLogFilter(c *gin.Context) (logs.Filter, bool) {
+	f := logs.Filter{
+		Level:     c.Query("level"),
+		Path:      c.Query("path"),
+		RequestID: c.Query("request_id"),
+		Text:      c.Query("q"),
+	}
+
+	var err error
+	now := time.Now()
+	if v := c.Query("since"); v != "" {
+		if f.Since, err = logs.ParseTime(v, now); err != nil {
+			response.Fail(c, response.BadRequest(tr(c, "api.query.invalid_since")).WithDetail(errDetail(c, err)))
+			return f, false
+		}
+	}
+	if v := c.Query("until"); v != "" {
+		if f.Until, err = logs.ParseTime(v, now); err != nil {
+			response.Fail(c, response.BadRequest(tr(c, "api.query.invalid_until")).WithDetail(errDetail(c, err)))
+			return f, false
+		}
+	}
+	if err := f.Prepare(); err != nil {
+		response.Fail(c, response.BadRequest(tr(c, "api.logs.invalid_filter")).WithDetail(errDetail(c, err)))
+		return f, false
+	}
+	return f, true
+}
+
+// GetLogs 查询应用日志
+// @Summary 查询应用日志
+// @Description 在当前日志文件及轮转后的备份（含 gzip 压缩）中查询，按时间倒序分页返回。仅支持 JSON 编码的日志
+// @Tags Logging
+// @Produce json
+// @Param since query string false "起始时间：RFC3339、2006-01-02 15:04:05 或时长（如 1h 表示一小时前）"
+// @Param until query string false "结束时间，格式同 since"
+// @Param level query string false "最低日志级别" Enums(debug, info, warn, error)
+// @Param path query string false "请求路径前缀"
+// @Param request_id query string false "请求 ID"
+// @Param q query string false "全文检索，不区分大小写"
+// @Param limit query int false "每页数量" default(100)
+// @Param offset query int false "偏移量" default(0)
+// @Success 200 {object} response.PageBody{data=[]logs.Entry}
+// @Failure 400 {object} response.ErrorBody
+// @Failure 500 {object} response.ErrorBody
+// @Router /admin/logs [get]
+func GetLogs(c *gin.Context) {
+	f, ok := parseLogFilter(c)
+	if !ok {
+		return
+	}
+	f.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "100"))
+	f.Offset, _ = strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if f.Limit <= 0 || f.Limit > 1000 {
+		f.Limit = 100
+	}
+
+	entries, total, err := logs.Search(c.Request.Context(), config.GetLogConfig().Filename, f)
+	if err != nil {
+		response.Fail(c, response.Internal(tr(c, "api.logs.read_failed")).WithDetail(errDetail(c, err)).WithCause(err))
+		return
+	}
+	response.Page(c, entries, total, tr(c, "api.common.fetched"))
+}
+
+// TailLogs 实时跟踪应用日志
+// @Summary 实时跟踪应用日志
+// @Description 以 Server-Sent Events 推送新写入的日志，每条日志为一个 log 事件，data 为 JSON 格式的日志条目
+// @Tags Logging
+// @Produce text/event-stream
+// @Param level query string false "最低日志级别" Enums(debug, info, warn, error)
+// @Param path query string false "请求路径前缀"
+// @Param request_id query string false "请求 ID"
+// @Param q query string false "全文检索，不区分大小写"
+// @Success 200 {object} logs.Entry
+// @Failure 400 {object} response.ErrorBody
+// @Router /admin/logs/tail [get]
+func TailLogs(c *gin.Context) {
+	f, ok := parseLogFilter(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	entries := make(chan logs.Entry, 64)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- logs.Follow(ctx, config.GetLogConfig().Filename, f, func(e logs.Entry) error {
+			select {
+			case entries <- e:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	keepAlive := time.NewTicker(logKeepAlive)
+	defer keepAlive.Stop()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case e := <-entries:
+			c.SSEvent("log", e)
+		case <-keepAlive.C:
+			c.Writer.WriteString(": keep-alive\n\n")
+		case err := <-errCh:
+			if err != nil {
+				c.SSEvent("error", errDetail(c, err))
+			}
+			return false
+		case <-ctx.Done():
+			return false
+		}
+		return true
+	})
+}
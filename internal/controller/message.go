@@ -0,0 +1,17 @@
+package controller
+
+import (
+	"HarborArk/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// tr 按请求语言返回消息
+func tr(c *gin.Context, key string, args ...interface{}) string {
+	return i18n.Ctx(c.Request.Context()).T(key, args...)
+}
+
+// errDetail 按请求语言返回错误信息，用作错误响应的补充说明
+func errDetail(c *gin.Context, err error) string {
+	return i18n.Ctx(c.Request.Context()).Error(err)
+}
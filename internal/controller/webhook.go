@@ -0,0 +1,197 @@
+package controller
+
+import (
+	"HarborArk/internal/response"
+	"HarborArk/internal/service/audit"
+	"HarborArk/internal/service/webhook"
+	"HarborArk/internal/validation"
+	"errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookRequest 注册 Webhook 请求
+type WebhookRequest struct {
+	URL     string   `json:"url" binding:"required,http_url" format:"uri" example:"http://homeassistant.local:8123/api/webhook/nas"`
+	Secret  string   `json:"secret" example:"s3cr3t"`
+	Events  []string `json:"events" binding:"required,min=1,dive,required" example:"user.*,file.uploaded"`
+	Enabled *bool    `json:"enabled" example:"true"`
+}
+
+// webhookDispatcher 获取投递器，未启用时返回 503
+func webhookDispatcher(c *gin.Context) *webhook.Dispatcher {
+	d := webhook.Default()
+	if d == nil {
+		response.Fail(c, response.Unavailable(tr(c, "api.webhook.disabled")))
+	}
+	return d
+}
+
+// webhookError 将存储返回的错误转换为响应错误，Webhook 或投递记录不存在时返回 404
+func webhookError(c *gin.Context, err error) *response.Error {
+	switch {
+	case errors.Is(err, webhook.ErrHookNotFound):
+		return response.NotFound(tr(c, "api.webhook.not_found"))
+	case errors.Is(err, webhook.ErrDeliveryNotFound):
+		return response.NotFound(tr(c, "api.webhook.delivery_not_found"))
+	default:
+		return response.Internal(tr(c, "api.webhook.operation_failed")).WithDetail(errDetail(c, err)).WithCause(err)
+	}
+}
+
+// GetWebhooks 获取 Webhook 列表
+// @Summary 获取 Webhook 列表
+// @Description 获取所有已注册的 Webhook，不返回签名密钥
+// @Tags Webhook
+// @Produce json
+// @Success 200 {object} response.Body{data=[]webhook.Hook}
+// @Failure 503 {object} response.ErrorBody
+// @Router /admin/webhooks [get]
+func GetWebhooks(c *gin.Context) {
+	d := webhookDispatcher(c)
+	if d == nil {
+		return
+	}
+	hooks := d.Store().Hooks()
+	for i := range hooks {
+		hooks[i].Secret = ""
+	}
+	response.OK(c, hooks, tr(c, "api.common.fetched"))
+}
+
+// CreateWebhook 注册 Webhook
+// @Summary 注册 Webhook
+// @Description 注册事件推送地址，未提供密钥时自动生成；密钥仅在创建时返回一次
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param webhook body WebhookRequest true "Webhook 信息"
+// @Success 201 {object} response.Body{data=webhook.Hook}
+// @Failure 400 {object} response.ErrorBody{data=[]validation.FieldError}
+// @Failure 503 {object} response.ErrorBody
+// @Router /admin/webhooks [post]
+func CreateWebhook(c *gin.Context) {
+	d := webhookDispatcher(c)
+	if d == nil {
+		return
+	}
+
+	var req WebhookRequest
+	if !validation.BindJSON(c, &req) {
+		return
+	}
+
+	hook := webhook.NewHook(req.URL, req.Secret, req.Events, req.Enabled == nil || *req.Enabled)
+	if err := d.Store().AddHook(hook); err != nil {
+		response.Fail(c, response.Internal(tr(c, "api.webhook.save_failed")).WithDetail(errDetail(c, err)).WithCause(err))
+		return
+	}
+	recordAudit(c, audit.ActionWebhookCreate, "webhooks/"+hook.ID, audit.ResultSuccess, map[string]string{
+		"url":    hook.URL,
+		"events": strings.Join(hook.Events, ","),
+	})
+
+	response.Created(c, hook, tr(c, "api.common.created"))
+}
+
+// DeleteWebhook 删除 Webhook
+// @Summary 删除 Webhook
+// @Description 删除 Webhook 及其投递历史
+// @Tags Webhook
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} response.Body
+// @Failure 404 {object} response.ErrorBody
+// @Failure 503 {object} response.ErrorBody
+// @Router /admin/webhooks/{id} [delete]
+func DeleteWebhook(c *gin.Context) {
+	d := webhookDispatcher(c)
+	if d == nil {
+		return
+	}
+	id := c.Param("id")
+	if err := d.Store().DeleteHook(id); err != nil {
+		recordAudit(c, audit.ActionWebhookDelete, "webhooks/"+id, audit.ResultFailure, map[string]string{
+			"error": err.Error(),
+		})
+		response.Fail(c, webhookError(c, err))
+		return
+	}
+	recordAudit(c, audit.ActionWebhookDelete, "webhooks/"+id, audit.ResultSuccess, nil)
+	response.OK(c, nil, tr(c, "api.common.deleted"))
+}
+
+// GetWebhookDeliveries 获取投递历史
+// @Summary 获取 Webhook 投递历史
+// @Description 按时间倒序返回指定 Webhook 的投递记录
+// @Tags Webhook
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} response.Body{data=[]webhook.Delivery}
+// @Failure 404 {object} response.ErrorBody
+// @Failure 503 {object} response.ErrorBody
+// @Router /admin/webhooks/{id}/deliveries [get]
+func GetWebhookDeliveries(c *gin.Context) {
+	d := webhookDispatcher(c)
+	if d == nil {
+		return
+	}
+	id := c.Param("id")
+	if _, err := d.Store().Hook(id); err != nil {
+		response.Fail(c, webhookError(c, err))
+		return
+	}
+	response.OK(c, d.Store().Deliveries(id), tr(c, "api.common.fetched"))
+}
+
+// RedeliverWebhook 重新投递
+// @Summary 重新投递 Webhook 事件
+// @Description 以相同负载重新投递一条历史记录
+// @Tags Webhook
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param deliveryId path string true "投递记录 ID"
+// @Success 202 {object} response.Body{data=webhook.Delivery}
+// @Failure 404 {object} response.ErrorBody
+// @Failure 503 {object} response.ErrorBody
+// @Router /admin/webhooks/{id}/deliveries/{deliveryId}/redeliver [post]
+func RedeliverWebhook(c *gin.Context) {
+	d := webhookDispatcher(c)
+	if d == nil {
+		return
+	}
+	delivery, err := d.Redeliver(c.Param("id"), c.Param("deliveryId"))
+	if err != nil {
+		response.Fail(c, webhookError(c, err))
+		return
+	}
+	response.Accepted(c, delivery, tr(c, "api.webhook.queued"))
+}
+
+// PingWebhook 发送测试事件
+// @Summary 发送 Webhook 测试事件
+// @Description 向指定 Webhook 发送 webhook.ping 事件
+// @Tags Webhook
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 202 {object} response.Body
+// @Failure 404 {object} response.ErrorBody
+// @Failure 503 {object} response.ErrorBody
+// @Router /admin/webhooks/{id}/ping [post]
+func PingWebhook(c *gin.Context) {
+	d := webhookDispatcher(c)
+	if d == nil {
+		return
+	}
+	hook, err := d.Store().Hook(c.Param("id"))
+	if err != nil {
+		response.Fail(c, webhookError(c, err))
+		return
+	}
+	if err := d.Ping(hook.ID); err != nil {
+		response.Fail(c, response.Internal(tr(c, "api.webhook.ping_failed")).WithDetail(errDetail(c, err)).WithCause(err))
+		return
+	}
+	response.Accepted(c, nil, tr(c, "api.webhook.queued"))
+}
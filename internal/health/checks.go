@@ -0,0 +1,88 @@
+package health
+
+import (
+	"HarborArk/config"
+	"HarborArk/internal/i18n"
+	"HarborArk/internal/storage"
+	"context"
+	"errors"
+	"os"
+	"sort"
+	"time"
+)
+
+// VolumeCheck 检查存储卷已挂载且可写
+func VolumeCheck(vol config.VolumeConfig) Checker {
+	return CheckFunc(func(ctx context.Context) error {
+		info, err := os.Stat(vol.Path)
+		if err != nil {
+			return i18n.NewError("api.health.volume_unavailable", err)
+		}
+		if !info.IsDir() {
+			return i18n.NewError("api.health.volume_not_dir", vol.Path)
+		}
+
+		probe, err := os.CreateTemp(vol.Path, ".harborark-health-*")
+		if err != nil {
+			return i18n.NewError("api.health.volume_not_writable", err)
+		}
+		name := probe.Name()
+		_, werr := probe.Write([]byte("ok"))
+		probe.Close()
+		os.Remove(name)
+		if werr != nil {
+			return i18n.NewError("api.health.volume_write_failed", werr)
+		}
+		return nil
+	})
+}
+
+// FreeSpaceCheck 检查存储卷可用空间不低于 minPercent
+func FreeSpaceCheck(vol config.VolumeConfig, minPercent float64) Checker {
+	return CheckFunc(func(ctx context.Context) error {
+		usage, err := storage.DiskUsage(vol.Path)
+		if errors.Is(err, storage.ErrUnsupported) {
+			// 无法获取容量的平台上不检查可用空间，避免服务始终处于未就绪状态
+			return nil
+		}
+		if err != nil {
+			return i18n.NewError("api.health.disk_usage_failed", err)
+		}
+		if free := usage.FreePercent(); free < minPercent {
+			return i18n.NewError("api.health.low_free_space", free, minPercent)
+		}
+		return nil
+	})
+}
+
+// CertExpiryCheck 检查证书剩余有效期不少于 minValidity，expiry 返回各证书的到期时间
+func CertExpiryCheck(expiry func(ctx context.Context) (map[string]time.Time, error), minValidity time.Duration) Checker {
+	return CheckFunc(func(ctx context.Context) error {
+		certs, err := expiry(ctx)
+		if err != nil {
+			return err
+		}
+		var (
+			names    []string
+			problems []error
+		)
+		for name := range certs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			notAfter := certs[name]
+			left := time.Until(notAfter)
+			switch {
+			case left <= 0:
+				problems = append(problems, i18n.NewError("api.health.cert_expired", name, notAfter.Format(time.RFC3339)))
+			case left < minValidity:
+				problems = append(problems, i18n.NewError("api.health.cert_expiring", name, notAfter.Format(time.RFC3339)))
+			}
+		}
+		if len(problems) > 0 {
+			return i18n.NewError("api.health.certs_expiring", problems)
+		}
+		return nil
+	})
+}
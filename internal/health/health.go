@@ -0,0 +1,182 @@
+package health
+
+import (
+	"HarborArk/internal/i18n"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Kind 检查类型，可按位组合
+type Kind int
+
+const (
+	// Liveness 存活检查，失败表示进程需要重启
+	Liveness Kind = 1 << iota
+	// Readiness 就绪检查，失败表示暂时不应接收流量
+	Readiness
+)
+
+// 检查状态
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// Checker 健康检查项
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckFunc 函数形式的检查项
+type CheckFunc func(ctx context.Context) error
+
+// Check 实现 Checker
+func (f CheckFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Result 单项检查结果
+type Result struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report 汇总检查结果
+type Report struct {
+	Status string            `json:"status"`
+	Time   time.Time         `json:"time"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// OK 是否全部检查通过
+func (r Report) OK() bool {
+	return r.Status == StatusOK
+}
+
+type registration struct {
+	name    string
+	kind    Kind
+	checker Checker
+}
+
+// Registry 检查项注册表
+type Registry struct {
+	mu      sync.RWMutex
+	timeout time.Duration
+	checks  []registration
+}
+
+var std = NewRegistry(3 * time.Second)
+
+// NewRegistry 创建注册表，timeout 为单项检查的超时时间
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{timeout: timeout}
+}
+
+// Default 获取全局注册表
+func Default() *Registry {
+	return std
+}
+
+// Register 向全局注册表注册检查项
+func Register(name string, kind Kind, checker Checker) {
+	std.Register(name, kind, checker)
+}
+
+// SetTimeout 设置单项检查的超时时间
+func (r *Registry) SetTimeout(timeout time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.timeout = timeout
+}
+
+// Register 注册检查项，同名检查项会被替换
+func (r *Registry) Register(name string, kind Kind, checker Checker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, reg := range r.checks {
+		if reg.name == name {
+			r.checks[i] = registration{name: name, kind: kind, checker: checker}
+			return
+		}
+	}
+	r.checks = append(r.checks, registration{name: name, kind: kind, checker: checker})
+}
+
+// Run 并发执行指定类型的检查项并汇总结果
+func (r *Registry) Run(ctx context.Context, kind Kind) Report {
+	r.mu.RLock()
+	timeout := r.timeout
+	var checks []registration
+	for _, reg := range r.checks {
+		if reg.kind&kind != 0 {
+			checks = append(checks, reg)
+		}
+	}
+	r.mu.RUnlock()
+
+	report := Report{
+		Status: StatusOK,
+		Time:   time.Now(),
+		Checks: make(map[string]Result, len(checks)),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, reg := range checks {
+		wg.Add(1)
+		go func(reg registration) {
+			defer wg.Done()
+			result := runCheck(ctx, reg.checker, timeout)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[reg.name] = result
+			if result.Status != StatusOK {
+				report.Status = StatusFail
+			}
+		}(reg)
+	}
+	wg.Wait()
+
+	return report
+}
+
+// runCheck 在超时时间内执行单项检查，panic 视为失败
+func runCheck(ctx context.Context, checker Checker, timeout time.Duration) (result Result) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- i18n.NewError("api.health.panic", fmt.Sprint(p))
+			}
+		}()
+		done <- checker.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = i18n.NewError("api.health.timeout", ctx.Err())
+	}
+
+	result.LatencyMS = float64(time.Since(start).Microseconds()) / 1000
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = i18n.Ctx(ctx).Error(err)
+		return result
+	}
+	result.Status = StatusOK
+	return result
+}
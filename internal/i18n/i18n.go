@@ -0,0 +1,289 @@
+package i18n
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
+)
+
+// 支持的语言
+const (
+	ZhCN = "zh-CN"
+	EnUS = "en-US"
+)
+
+// Languages 支持的语言，第一项为默认语言
+var Languages = []string{ZhCN, EnUS}
+
+//go:embed locales/*.yaml
+var files embed.FS
+
+var (
+	// bundles 语言到消息的映射，键为以 . 分隔的消息路径
+	bundles = map[string]map[string]string{}
+	matcher language.Matcher
+	current atomic.Value
+)
+
+func init() {
+	tags := make([]language.Tag, 0, len(Languages))
+	for _, lang := range Languages {
+		data, err := files.ReadFile(path.Join("locales", lang+".yaml"))
+		if err != nil {
+			panic(fmt.Sprintf("i18n: 缺少语言包 %s", lang))
+		}
+		var tree map[string]interface{}
+		if err := yaml.Unmarshal(data, &tree); err != nil {
+			panic(fmt.Sprintf("i18n: 解析语言包 %s 失败: %v", lang, err))
+		}
+		bundle := map[string]string{}
+		flatten("", tree, bundle)
+		bundles[lang] = bundle
+		tags = append(tags, language.MustParse(lang))
+	}
+	matcher = language.NewMatcher(tags)
+	current.Store(Languages[0])
+}
+
+// flatten 将嵌套的语言包展开为以 . 分隔的键
+func flatten(prefix string, node map[string]interface{}, out map[string]string) {
+	for key, value := range node {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			flatten(key, v, out)
+		default:
+			out[key] = strings.TrimRight(fmt.Sprint(v), "\n")
+		}
+	}
+}
+
+// SetLanguage 设置进程默认语言，用于命令行输出以及未指定 Accept-Language 的请求
+func SetLanguage(lang string) {
+	current.Store(lang)
+}
+
+// Language 返回进程默认语言
+func Language() string {
+	return current.Load().(string)
+}
+
+// Parse 将 en、en_US.UTF-8、zh-Hans 等语言标识规范为支持的语言，无法匹配时返回 false
+func Parse(s string) (string, bool) {
+	s, _, _ = strings.Cut(s, ".")
+	s = strings.ReplaceAll(s, "_", "-")
+	tag, err := language.Parse(s)
+	if err != nil {
+		return "", false
+	}
+	_, index, confidence := matcher.Match(tag)
+	if confidence == language.No {
+		return "", false
+	}
+	return Languages[index], true
+}
+
+// FromEnv 按 LC_ALL、LC_MESSAGES、LANG 的顺序读取环境变量中的语言，未设置或不支持时返回空
+func FromEnv() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		// C 与 POSIX 表示未指定语言
+		if value == "C" || value == "POSIX" || strings.HasPrefix(value, "C.") {
+			return ""
+		}
+		lang, _ := Parse(value)
+		return lang
+	}
+	return ""
+}
+
+// Match 根据 Accept-Language 请求头选择语言，未指定或无法匹配时使用进程默认语言
+func Match(acceptLanguage string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return Language()
+	}
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return Language()
+	}
+	return Languages[index]
+}
+
+// Lookup 返回指定语言中的消息，不存在时返回 false
+func Lookup(lang, key string) (string, bool) {
+	msg, ok := bundles[lang][key]
+	return msg, ok
+}
+
+// Printer 按指定语言输出消息
+type Printer string
+
+// T 返回消息，有参数时按 fmt 格式化。当前语言缺少该消息时使用默认语言，仍不存在时返回键本身
+func (p Printer) T(key string, args ...interface{}) string {
+	msg, ok := Lookup(string(p), key)
+	if !ok {
+		if msg, ok = Lookup(Languages[0], key); !ok {
+			return key
+		}
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// Errorf 返回以消息为格式的错误，支持 %w
+func (p Printer) Errorf(key string, args ...interface{}) error {
+	msg := p.T(key)
+	return fmt.Errorf(msg, args...)
+}
+
+// T 按进程默认语言返回消息
+func T(key string, args ...interface{}) string {
+	return Printer(Language()).T(key, args...)
+}
+
+// Errorf 按进程默认语言返回以消息为格式的错误
+func Errorf(key string, args ...interface{}) error {
+	return Printer(Language()).Errorf(key, args...)
+}
+
+// Error 延迟翻译的错误，由不知道请求语言的包返回，在输出时按请求语言翻译
+type Error struct {
+	Key  string
+	Args []interface{}
+}
+
+// NewError 返回以消息为格式的错误。参数中的 error 按同一语言翻译，[]error 翻译后以 ; 连接，第一个 error 参数作为被包装的错误
+func NewError(key string, args ...interface{}) error {
+	return &Error{Key: key, Args: args}
+}
+
+// Error 按进程默认语言返回错误信息
+func (e *Error) Error() string {
+	return e.In(Printer(Language()))
+}
+
+// In 按指定语言返回错误信息
+func (e *Error) In(p Printer) string {
+	args := make([]interface{}, len(e.Args))
+	for i, arg := range e.Args {
+		switch v := arg.(type) {
+		case error:
+			args[i] = p.Error(v)
+		case []error:
+			msgs := make([]string, len(v))
+			for j, err := range v {
+				msgs[j] = p.Error(err)
+			}
+			args[i] = strings.Join(msgs, "; ")
+		default:
+			args[i] = arg
+		}
+	}
+	return p.T(e.Key, args...)
+}
+
+// Unwrap 返回第一个 error 参数
+func (e *Error) Unwrap() error {
+	for _, arg := range e.Args {
+		if err, ok := arg.(error); ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// Error 返回错误信息，err 或其包装的错误为 *Error 时按该语言翻译
+func (p Printer) Error(err error) string {
+	var e *Error
+	switch {
+	case !errors.As(err, &e):
+		return err.Error()
+	case error(e) == err:
+		return e.In(p)
+	default:
+		// 外层为普通错误时只替换被包装的部分
+		return strings.Replace(err.Error(), e.Error(), e.In(p), 1)
+	}
+}
+
+type ctxKey struct{}
+
+// WithLanguage 返回携带请求语言的 context
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, lang)
+}
+
+// Ctx 返回请求语言的 Printer，context 中没有语言时使用进程默认语言
+func Ctx(ctx context.Context) Printer {
+	if lang, ok := ctx.Value(ctxKey{}).(string); ok {
+		return Printer(lang)
+	}
+	return Printer(Language())
+}
+
+// Keys 返回指定语言中的全部消息键，已排序
+func Keys(lang string) []string {
+	keys := make([]string, 0, len(bundles[lang]))
+	for key := range bundles[lang] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// placeholderPattern 匹配 fmt 格式动词与 {0} 形式的占位符
+var placeholderPattern = regexp.MustCompile(`%[-+# 0]*\d*(?:\.\d+)?[a-zA-Z]|\{\d+\}`)
+
+// placeholders 返回消息中的占位符，用于比较不同语言的消息参数是否一致
+func placeholders(msg string) string {
+	return strings.Join(placeholderPattern.FindAllString(strings.ReplaceAll(msg, "%%", ""), -1), " ")
+}
+
+// Check 检查每个消息键是否在所有语言包中都存在，且各语言的占位符一致
+func Check() []string {
+	all := map[string]bool{}
+	for _, lang := range Languages {
+		for key := range bundles[lang] {
+			all[key] = true
+		}
+	}
+	keys := make([]string, 0, len(all))
+	for key := range all {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var problems []string
+	base := Languages[0]
+	for _, key := range keys {
+		ref, hasRef := bundles[base][key]
+		for _, lang := range Languages {
+			msg, ok := bundles[lang][key]
+			switch {
+			case !ok:
+				problems = append(problems, T("cli.i18n.check.missing", lang, key))
+			case hasRef && lang != base && placeholders(msg) != placeholders(ref):
+				problems = append(problems, T("cli.i18n.check.placeholders", lang, key, placeholders(msg), base, placeholders(ref)))
+			}
+		}
+	}
+	return problems
+}
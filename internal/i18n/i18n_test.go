@@ -0,0 +1,69 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	for _, problem := range Check() {
+		t.Error(problem)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"zh-CN", ZhCN, true},
+		{"zh_CN.UTF-8", ZhCN, true},
+		{"zh-Hans", ZhCN, true},
+		{"en", EnUS, true},
+		{"en_US.UTF-8", EnUS, true},
+		{"en-GB", EnUS, true},
+		{"", "", false},
+		{"??", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := Parse(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Parse(%q) = %q, %v, want %q, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestPrinterT(t *testing.T) {
+	en := Printer(EnUS)
+	if got := en.T("errors.config.invalid_profile", "staging"); got != "Unsupported profile: staging, available: dev, test, prod" {
+		t.Errorf("T() = %q", got)
+	}
+	if got := en.T("no.such.key"); got != "no.such.key" {
+		t.Errorf("缺少消息时应返回键本身，T() = %q", got)
+	}
+}
+
+func TestError(t *testing.T) {
+	inner := NewError("errors.config.env_not_set", "DB_PASSWORD")
+	err := NewError("errors.config.resolve_failed", "${env:DB_PASSWORD}", inner)
+
+	if !errors.Is(err, inner) {
+		t.Error("errors.Is() 应能找到被包装的错误")
+	}
+	want := "Cannot resolve ${env:DB_PASSWORD}: Environment variable DB_PASSWORD is not set"
+	if got := Printer(EnUS).Error(err); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if got := Ctx(WithLanguage(context.Background(), ZhCN)).Error(err); got != "无法解析 ${env:DB_PASSWORD}: 环境变量 DB_PASSWORD 未设置" {
+		t.Errorf("Error() = %q", got)
+	}
+
+	// 外层为普通错误时只翻译被包装的部分
+	wrapped := fmt.Errorf("config show: %w", inner)
+	if got := Printer(EnUS).Error(wrapped); got != "config show: Environment variable DB_PASSWORD is not set" {
+		t.Errorf("Error() = %q", got)
+	}
+}
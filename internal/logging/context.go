@@ -0,0 +1,37 @@
+package logging
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type (
+	loggerKey    struct{}
+	requestIDKey struct{}
+)
+
+// WithContext 将日志器保存到 context 中
+func WithContext(ctx context.Context, l *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// Ctx 返回 context 中的日志器，没有时返回全局日志器
+func Ctx(ctx context.Context) *zap.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*zap.Logger); ok {
+		return l
+	}
+	return zap.L()
+}
+
+// WithRequestID 保存请求 ID，并为 context 中的日志器附加 request_id 字段
+func WithRequestID(ctx context.Context, id string) context.Context {
+	ctx = context.WithValue(ctx, requestIDKey{}, id)
+	return WithContext(ctx, Ctx(ctx).With(zap.String("request_id", id)))
+}
+
+// RequestID 返回 context 中的请求 ID，不在请求中时返回空字符串
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
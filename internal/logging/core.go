@@ -0,0 +1,49 @@
+package logging
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// core 按日志器名称决定是否输出，底层输出自身不再按全局级别过滤
+type core struct {
+	zapcore.Core
+	// debug 为 true 时忽略级别设置，用于匹配临时调试规则的请求
+	debug bool
+}
+
+// NewCore 包装底层输出，使其遵循全局级别、日志器级别与临时调试规则
+func NewCore(inner zapcore.Core) zapcore.Core {
+	return &core{Core: inner}
+}
+
+func (c *core) Enabled(l zapcore.Level) bool {
+	return c.debug || l >= zapcore.Level(minLevel.Load())
+}
+
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	return &core{Core: c.Core.With(fields), debug: c.debug}
+}
+
+func (c *core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.debug && ent.Level < LoggerLevel(ent.LoggerName) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// Named 返回指定组件的日志器，可通过 SetLoggerLevel 单独调整级别。
+// 每次调用时获取，以便使用 Init 之后替换的全局日志器
+func Named(name string) *zap.Logger {
+	return zap.L().Named(name)
+}
+
+// ForceDebug 返回忽略级别设置、输出全部日志的日志器
+func ForceDebug(l *zap.Logger) *zap.Logger {
+	return l.WithOptions(zap.WrapCore(func(inner zapcore.Core) zapcore.Core {
+		if c, ok := inner.(*core); ok {
+			return &core{Core: c.Core, debug: true}
+		}
+		return inner
+	}))
+}
@@ -0,0 +1,208 @@
+package logging
+
+import (
+	"HarborArk/internal/i18n"
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// MaxDebugDuration 临时调试规则的最长有效期
+const MaxDebugDuration = 24 * time.Hour
+
+var (
+	// global 未单独设置级别的日志器使用的级别
+	global = zap.NewAtomicLevelAt(zapcore.InfoLevel)
+
+	mu sync.RWMutex
+	// loggers 按名称设置的日志器级别，名称为 webhook 时同样作用于 webhook.store 等子日志器
+	loggers = map[string]zapcore.Level{}
+	// rules 临时调试规则
+	rules = map[string]*DebugRule{}
+	// minLevel 所有规则中最低的级别，用于快速跳过不会输出的日志
+	minLevel atomic.Int32
+)
+
+// DebugRule 临时调试规则，匹配的请求输出调试日志，到期后自动恢复
+type DebugRule struct {
+	ID        string    `json:"id"`
+	User      string    `json:"user,omitempty"`
+	Path      string    `json:"path,omitempty"`
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	timer *time.Timer
+}
+
+// Matches 判断请求是否匹配规则，同时指定用户与路径时需同时满足，路径按前缀匹配
+func (r *DebugRule) Matches(user, path string) bool {
+	if r.User != "" && r.User != user {
+		return false
+	}
+	return r.Path == "" || strings.HasPrefix(path, r.Path)
+}
+
+// ParseLevel 解析日志级别
+func ParseLevel(text string) (zapcore.Level, error) {
+	var l zapcore.Level
+	if err := l.UnmarshalText([]byte(text)); err != nil {
+		return l, i18n.NewError("api.logging.parse_level_failed", err)
+	}
+	return l, nil
+}
+
+// Level 返回全局日志级别
+func Level() zapcore.Level {
+	return global.Level()
+}
+
+// SetLevel 修改全局日志级别，立即对所有未单独设置级别的日志器生效
+func SetLevel(text string) error {
+	l, err := ParseLevel(text)
+	if err != nil {
+		return err
+	}
+	global.SetLevel(l)
+	updateMinLevel()
+	return nil
+}
+
+// LoggerLevel 返回指定名称的日志器实际使用的级别
+func LoggerLevel(name string) zapcore.Level {
+	mu.RLock()
+	defer mu.RUnlock()
+	return loggerLevel(name)
+}
+
+// loggerLevel 按名称由长到短查找单独设置的级别，调用方需持有读锁
+func loggerLevel(name string) zapcore.Level {
+	for name != "" {
+		if l, ok := loggers[name]; ok {
+			return l
+		}
+		i := strings.LastIndex(name, ".")
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	return global.Level()
+}
+
+// LoggerLevels 返回单独设置了级别的日志器
+func LoggerLevels() map[string]string {
+	mu.RLock()
+	defer mu.RUnlock()
+	levels := make(map[string]string, len(loggers))
+	for name, l := range loggers {
+		levels[name] = l.String()
+	}
+	return levels
+}
+
+// SetLoggerLevel 为指定名称的日志器及其子日志器单独设置级别
+func SetLoggerLevel(name, text string) error {
+	if name == "" {
+		return i18n.NewError("api.logging.logger_name_required")
+	}
+	l, err := ParseLevel(text)
+	if err != nil {
+		return err
+	}
+	mu.Lock()
+	loggers[name] = l
+	mu.Unlock()
+	updateMinLevel()
+	return nil
+}
+
+// ResetLoggerLevel 取消日志器单独设置的级别，恢复使用全局级别
+func ResetLoggerLevel(name string) bool {
+	mu.Lock()
+	_, ok := loggers[name]
+	delete(loggers, name)
+	mu.Unlock()
+	updateMinLevel()
+	return ok
+}
+
+// AddDebugRule 添加临时调试规则，d 到期后自动删除
+func AddDebugRule(user, path string, d time.Duration) (DebugRule, error) {
+	if user == "" && path == "" {
+		return DebugRule{}, i18n.NewError("api.logging.debug_target_required")
+	}
+	if d <= 0 || d > MaxDebugDuration {
+		return DebugRule{}, i18n.NewError("api.logging.debug_duration_range", MaxDebugDuration.String())
+	}
+	b := make([]byte, 6)
+	rand.Read(b)
+	rule := &DebugRule{ID: hex.EncodeToString(b), User: user, Path: path, ExpiresAt: time.Now().Add(d)}
+
+	// 持有锁时先登记规则再启动定时器，避免定时器在规则登记前触发而无法删除
+	mu.Lock()
+	rules[rule.ID] = rule
+	rule.timer = time.AfterFunc(d, func() {
+		if RemoveDebugRule(rule.ID) {
+			zap.L().Info("临时调试规则已到期，恢复原日志级别", zap.String("rule", rule.ID))
+		}
+	})
+	mu.Unlock()
+	return *rule, nil
+}
+
+// RemoveDebugRule 删除临时调试规则
+func RemoveDebugRule(id string) bool {
+	mu.Lock()
+	rule, ok := rules[id]
+	if ok {
+		rule.timer.Stop()
+		delete(rules, id)
+	}
+	mu.Unlock()
+	return ok
+}
+
+// DebugRules 返回按到期时间排序的临时调试规则
+func DebugRules() []DebugRule {
+	mu.RLock()
+	defer mu.RUnlock()
+	list := make([]DebugRule, 0, len(rules))
+	for _, rule := range rules {
+		list = append(list, *rule)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ExpiresAt.Before(list[j].ExpiresAt)
+	})
+	return list
+}
+
+// MatchDebug 返回匹配请求的临时调试规则
+func MatchDebug(user, path string) (DebugRule, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, rule := range rules {
+		if rule.Matches(user, path) && time.Now().Before(rule.ExpiresAt) {
+			return *rule, true
+		}
+	}
+	return DebugRule{}, false
+}
+
+// updateMinLevel 重新计算最低级别。临时调试通过请求日志器输出，不参与计算
+func updateMinLevel() {
+	mu.RLock()
+	defer mu.RUnlock()
+	lowest := global.Level()
+	for _, l := range loggers {
+		if l < lowest {
+			lowest = l
+		}
+	}
+	minLevel.Store(int32(lowest))
+}
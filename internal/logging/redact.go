@@ -0,0 +1,258 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// RedactRules 日志脱敏规则
+type RedactRules struct {
+	Headers []string
+	Query   []string
+	Fields  []string
+	Mask    string
+}
+
+// redactor 归一化后的脱敏规则
+type redactor struct {
+	headers map[string]bool
+	query   map[string]bool
+	fields  map[string]bool
+	mask    string
+}
+
+// redaction 当前生效的脱敏规则，未设置时不脱敏
+var redaction atomic.Pointer[redactor]
+
+// SetRedaction 设置脱敏规则，可在运行时调用
+func SetRedaction(rules RedactRules) {
+	set := func(names []string) map[string]bool {
+		m := make(map[string]bool, len(names))
+		for _, name := range names {
+			m[normalize(name)] = true
+		}
+		return m
+	}
+	redaction.Store(&redactor{
+		headers: set(rules.Headers),
+		query:   set(rules.Query),
+		fields:  set(rules.Fields),
+		mask:    rules.Mask,
+	})
+}
+
+// normalize 名称不区分大小写并忽略 - 与 _
+func normalize(name string) string {
+	name = strings.ToLower(name)
+	if strings.ContainsAny(name, "-_") {
+		name = strings.NewReplacer("-", "", "_", "").Replace(name)
+	}
+	return name
+}
+
+// RedactHeader 返回隐藏敏感请求头后的副本
+func RedactHeader(h http.Header) http.Header {
+	r := redaction.Load()
+	out := h.Clone()
+	if r == nil {
+		return out
+	}
+	for key, values := range out {
+		if r.headers[normalize(key)] {
+			for i := range values {
+				values[i] = r.mask
+			}
+		}
+	}
+	return out
+}
+
+// RedactQuery 隐藏查询字符串或表单中的敏感参数，保持参数原有顺序
+func RedactQuery(raw string) string {
+	r := redaction.Load()
+	if r == nil || raw == "" {
+		return raw
+	}
+	parts := strings.Split(raw, "&")
+	for i, part := range parts {
+		key, _, _ := strings.Cut(part, "=")
+		if name, err := url.QueryUnescape(key); err == nil && r.query[normalize(name)] {
+			parts[i] = key + "=" + r.mask
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// RedactURL 隐藏 URL 中的敏感查询参数与用户密码
+func RedactURL(raw string) string {
+	if redaction.Load() == nil {
+		return raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	u.RawQuery = RedactQuery(u.RawQuery)
+	return u.Redacted()
+}
+
+// RedactJSON 隐藏 JSON 中的敏感字段，包括嵌套对象与数组中的字段。不是合法 JSON 时返回 false
+func RedactJSON(data []byte) ([]byte, bool) {
+	r := redaction.Load()
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, false
+	}
+	if r == nil || !r.redactValue(v) {
+		return data, true
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return nil, false
+	}
+	return out, true
+}
+
+// redactValue 原地隐藏敏感字段，返回是否有修改
+func (r *redactor) redactValue(v interface{}) bool {
+	changed := false
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if r.fields[normalize(key)] {
+				v[key] = r.mask
+				changed = true
+			} else if r.redactValue(item) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if r.redactValue(item) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// RedactMap 返回隐藏敏感字段后的副本
+func RedactMap(m map[string]string) map[string]string {
+	r := redaction.Load()
+	if r == nil || m == nil {
+		return m
+	}
+	out := make(map[string]string, len(m))
+	for key, value := range m {
+		if r.fields[normalize(key)] {
+			value = r.mask
+		}
+		out[key] = value
+	}
+	return out
+}
+
+// DumpRequest 以 HTTP/1.x 格式输出请求行与请求头，敏感请求头与查询参数已隐藏，不包含请求体
+func DumpRequest(req *http.Request) string {
+	clone := req.Clone(req.Context())
+	clone.Header = RedactHeader(req.Header)
+	clone.URL.RawQuery = RedactQuery(req.URL.RawQuery)
+	clone.RequestURI = clone.URL.RequestURI()
+	data, _ := httputil.DumpRequest(clone, false)
+	return string(data)
+}
+
+// Redact 包装单个输出，写入前隐藏敏感日志字段：名称匹配的字段替换为占位符，
+// query 字段按查询字符串、url 字段与请求错误中的 URL 按 URL 处理，zap.Any 记录的结构体与映射按 JSON 逐层处理。
+// 需包装在具体输出上而不是 NewTee 的结果上，以保留各输出自身的级别
+func Redact(inner zapcore.Core) zapcore.Core {
+	return &redactCore{Core: inner}
+}
+
+type redactCore struct {
+	zapcore.Core
+}
+
+func (c *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactCore{Core: c.Core.With(redactFields(fields))}
+}
+
+func (c *redactCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *redactCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, redactFields(fields))
+}
+
+// redactFields 返回隐藏敏感值后的字段，没有需要隐藏的内容时返回原切片
+func redactFields(fields []zapcore.Field) []zapcore.Field {
+	r := redaction.Load()
+	if r == nil {
+		return fields
+	}
+	var out []zapcore.Field
+	for i, f := range fields {
+		redacted, ok := r.redactField(f)
+		if !ok {
+			continue
+		}
+		if out == nil {
+			out = make([]zapcore.Field, len(fields))
+			copy(out, fields)
+		}
+		out[i] = redacted
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+// redactField 返回隐藏敏感值后的字段，无需修改时返回 false
+func (r *redactor) redactField(f zapcore.Field) (zapcore.Field, bool) {
+	name := normalize(f.Key)
+	switch {
+	case r.fields[name]:
+		return zap.String(f.Key, r.mask), true
+	case f.Type == zapcore.StringType && name == "query":
+		if s := RedactQuery(f.String); s != f.String {
+			return zap.String(f.Key, s), true
+		}
+	case f.Type == zapcore.StringType && name == "url":
+		if s := RedactURL(f.String); s != f.String {
+			return zap.String(f.Key, s), true
+		}
+	case f.Type == zapcore.ErrorType:
+		// 请求失败的错误信息包含完整 URL，如 webhook 地址中的 token
+		var ue *url.Error
+		if err, ok := f.Interface.(error); ok && errors.As(err, &ue) {
+			if u := RedactURL(ue.URL); u != ue.URL {
+				return zap.String(f.Key, strings.ReplaceAll(err.Error(), ue.URL, u)), true
+			}
+		}
+	case f.Type == zapcore.ReflectType && f.Interface != nil:
+		data, err := json.Marshal(f.Interface)
+		if err != nil {
+			break
+		}
+		if out, ok := RedactJSON(data); ok && !bytes.Equal(out, data) {
+			return zap.Reflect(f.Key, json.RawMessage(out)), true
+		}
+	}
+	return f, false
+}
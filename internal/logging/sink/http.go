@@ -0,0 +1,377 @@
+package sink
+
+import (
+	"HarborArk/config"
+	"HarborArk/internal/metrics"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// HTTP 输出的默认值
+const (
+	defaultBatchSize     = 500
+	defaultBufferSize    = 10000
+	defaultFlushInterval = time.Second
+	defaultHTTPTimeout   = 10 * time.Second
+	defaultMaxRetries    = 3
+	maxRetryBackoff      = 10 * time.Second
+)
+
+// record 等待推送的一条日志
+type record struct {
+	time   time.Time
+	level  zapcore.Level
+	logger string
+	line   []byte
+}
+
+// newHTTPSink 创建批量推送到 Loki 或 Elasticsearch 的输出
+func newHTTPSink(cfg config.LogSinkConfig, level zapcore.Level, encoderConfig zapcore.EncoderConfig) (*Sink, error) {
+	if cfg.Format == "" {
+		cfg.Format = "loki"
+	}
+	if cfg.BatchSize == 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	if cfg.BufferSize == 0 {
+		cfg.BufferSize = defaultBufferSize
+	}
+	if cfg.FlushInterval == 0 {
+		cfg.FlushInterval = defaultFlushInterval
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = defaultHTTPTimeout
+	}
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = defaultMaxRetries
+	}
+	if cfg.Overflow == "" {
+		cfg.Overflow = "drop"
+	}
+
+	switch cfg.Format {
+	case "loki":
+		// Loki 单独记录时间戳
+		if cfg.Encoding == "journald" {
+			cfg.Encoding = "console"
+		}
+	case "elasticsearch":
+		// 文档必须是 JSON，时间使用 Elasticsearch 默认识别的格式
+		if cfg.Index == "" {
+			cfg.Index = "harborark-{date}"
+		}
+		cfg.Encoding = "json"
+		encoderConfig.TimeKey = "@timestamp"
+		encoderConfig.EncodeTime = zapcore.RFC3339NanoTimeEncoder
+	default:
+		return nil, fmt.Errorf("日志输出 %s: 不支持的格式 %q", cfg.Name, cfg.Format)
+	}
+
+	s := &shipper{
+		cfg:      cfg,
+		client:   &http.Client{Timeout: cfg.Timeout},
+		queue:    make(chan record, cfg.BufferSize),
+		flushReq: make(chan chan struct{}),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+		reporter: reporter{name: cfg.Name},
+	}
+	go s.run()
+	c := &httpCore{LevelEnabler: level, enc: newEncoder(cfg.Encoding, encoderConfig), s: s}
+	return &Sink{Name: cfg.Name, Core: c, close: s.close}, nil
+}
+
+// httpCore 编码日志并放入推送队列
+type httpCore struct {
+	zapcore.LevelEnabler
+	enc zapcore.Encoder
+	s   *shipper
+}
+
+func (c *httpCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.enc = c.enc.Clone()
+	for _, f := range fields {
+		f.AddTo(clone.enc)
+	}
+	return &clone
+}
+
+func (c *httpCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *httpCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	line := bytes.TrimRight(buf.Bytes(), "\n")
+	c.s.enqueue(record{
+		time:   ent.Time,
+		level:  ent.Level,
+		logger: ent.LoggerName,
+		line:   append([]byte(nil), line...),
+	})
+	buf.Free()
+	if ent.Level > zapcore.ErrorLevel {
+		// panic 与 fatal 之后进程可能退出
+		c.s.flush()
+	}
+	return nil
+}
+
+func (c *httpCore) Sync() error {
+	c.s.flush()
+	return nil
+}
+
+// shipper 在后台按批次推送日志。队列满时按 overflow 丢弃新日志，
+// 或阻塞写日志的调用方最多 timeout 后再丢弃；推送失败重试期间不再读取队列，由队列承担背压
+type shipper struct {
+	cfg      config.LogSinkConfig
+	client   *http.Client
+	queue    chan record
+	flushReq chan chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+	once     sync.Once
+	reporter reporter
+}
+
+// enqueue 放入队列，队列已满时按 overflow 处理
+func (s *shipper) enqueue(r record) {
+	select {
+	case s.queue <- r:
+		return
+	default:
+	}
+	if s.cfg.Overflow == "block" {
+		timer := time.NewTimer(s.cfg.Timeout)
+		defer timer.Stop()
+		select {
+		case s.queue <- r:
+			return
+		case <-timer.C:
+		case <-s.done:
+		}
+	}
+	metrics.AddLogSinkEntries(s.cfg.Name, "dropped", 1)
+	s.reporter.report("缓冲区已满 (%d 条)，丢弃日志", s.cfg.BufferSize)
+}
+
+// flush 立即推送队列中的日志并等待完成，最多等待两倍请求超时
+func (s *shipper) flush() {
+	wait := time.NewTimer(2 * s.cfg.Timeout)
+	defer wait.Stop()
+	flushed := make(chan struct{})
+	select {
+	case s.flushReq <- flushed:
+	case <-s.done:
+		return
+	case <-wait.C:
+		return
+	}
+	select {
+	case <-flushed:
+	case <-wait.C:
+	}
+}
+
+// close 推送剩余日志后停止
+func (s *shipper) close() {
+	s.once.Do(func() {
+		close(s.stop)
+		<-s.done
+	})
+}
+
+func (s *shipper) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]record, 0, s.cfg.BatchSize)
+	send := func() {
+		if len(batch) > 0 {
+			s.send(batch)
+			batch = batch[:0]
+		}
+	}
+	// drain 取出队列中已有的全部日志
+	drain := func() {
+		for {
+			select {
+			case r := <-s.queue:
+				if batch = append(batch, r); len(batch) >= s.cfg.BatchSize {
+					send()
+				}
+			default:
+				send()
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case r := <-s.queue:
+			if batch = append(batch, r); len(batch) >= s.cfg.BatchSize {
+				send()
+			}
+		case <-ticker.C:
+			send()
+		case flushed := <-s.flushReq:
+			drain()
+			close(flushed)
+		case <-s.stop:
+			drain()
+			return
+		}
+	}
+}
+
+// send 推送一批日志，网络错误、429 与 5xx 响应按指数退避重试
+func (s *shipper) send(batch []record) {
+	var (
+		body        []byte
+		contentType string
+	)
+	if s.cfg.Format == "elasticsearch" {
+		body, contentType = s.bulkBody(batch), "application/x-ndjson"
+	} else {
+		body, contentType = s.lokiBody(batch), "application/json"
+	}
+
+	backoff := 500 * time.Millisecond
+	for attempt := 0; ; attempt++ {
+		retry, err := s.post(body, contentType)
+		if err == nil {
+			metrics.AddLogSinkEntries(s.cfg.Name, "sent", len(batch))
+			return
+		}
+		if !retry || attempt >= s.cfg.MaxRetries {
+			metrics.AddLogSinkEntries(s.cfg.Name, "failed", len(batch))
+			s.reporter.report("推送 %d 条日志失败: %v", len(batch), err)
+			return
+		}
+		select {
+		case <-time.After(backoff):
+		case <-s.stop:
+			// 关闭时不再等待重试
+			metrics.AddLogSinkEntries(s.cfg.Name, "failed", len(batch))
+			return
+		}
+		if backoff *= 2; backoff > maxRetryBackoff {
+			backoff = maxRetryBackoff
+		}
+	}
+}
+
+// post 发送请求，返回失败时是否值得重试
+func (s *shipper) post(body []byte, contentType string) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, s.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	for key, value := range s.cfg.Headers {
+		req.Header.Set(key, value)
+	}
+	if s.cfg.Username != "" {
+		req.SetBasicAuth(s.cfg.Username, s.cfg.Password)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode >= 300 {
+		retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return retry, fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if s.cfg.Format == "elasticsearch" {
+		// bulk 接口部分失败时仍返回 200
+		var result struct {
+			Errors bool `json:"errors"`
+		}
+		if json.Unmarshal(data, &result) == nil && result.Errors {
+			return false, fmt.Errorf("部分文档写入失败: %s", truncate(string(data), 512))
+		}
+	}
+	return false, nil
+}
+
+// lokiBody 按级别与日志器分组生成 Loki push 请求
+func (s *shipper) lokiBody(batch []record) []byte {
+	type stream struct {
+		Stream map[string]string `json:"stream"`
+		Values [][2]string       `json:"values"`
+	}
+	streams := map[string]*stream{}
+	var keys []string
+	for _, r := range batch {
+		key := r.level.String() + "\x00" + r.logger
+		st, ok := streams[key]
+		if !ok {
+			labels := make(map[string]string, len(s.cfg.Labels)+2)
+			for k, v := range s.cfg.Labels {
+				labels[k] = v
+			}
+			labels["level"] = r.level.String()
+			if r.logger != "" {
+				labels["logger"] = r.logger
+			}
+			st = &stream{Stream: labels}
+			streams[key] = st
+			keys = append(keys, key)
+		}
+		st.Values = append(st.Values, [2]string{strconv.FormatInt(r.time.UnixNano(), 10), string(r.line)})
+	}
+	sort.Strings(keys)
+
+	payload := struct {
+		Streams []*stream `json:"streams"`
+	}{Streams: make([]*stream, 0, len(keys))}
+	for _, key := range keys {
+		payload.Streams = append(payload.Streams, streams[key])
+	}
+	body, _ := json.Marshal(payload)
+	return body
+}
+
+// bulkBody 生成 Elasticsearch bulk 请求，索引名中的 {date} 替换为日志日期
+func (s *shipper) bulkBody(batch []record) []byte {
+	var buf bytes.Buffer
+	for _, r := range batch {
+		index := strings.ReplaceAll(s.cfg.Index, "{date}", r.time.UTC().Format("2006.01.02"))
+		action, _ := json.Marshal(map[string]map[string]string{"index": {"_index": index}})
+		buf.Write(action)
+		buf.WriteByte('\n')
+		buf.Write(r.line)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n] + "..."
+	}
+	return s
+}
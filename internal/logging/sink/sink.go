@@ -0,0 +1,131 @@
+package sink
+
+import (
+	"HarborArk/config"
+	"HarborArk/internal/logging"
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// Sink 日志文件之外的一个输出
+type Sink struct {
+	Name string
+	Core zapcore.Core
+	// close 停止后台推送并发送剩余日志，可为空
+	close func()
+}
+
+// Close 停止输出，发送缓冲中剩余的日志
+func (s *Sink) Close() {
+	if s.close != nil {
+		s.close()
+	}
+}
+
+// New 根据配置创建输出，index 为其在 logger.sinks 中的序号，用于生成默认名称
+func New(cfg config.LogSinkConfig, index int, encoderConfig zapcore.EncoderConfig) (*Sink, error) {
+	if cfg.Name == "" {
+		cfg.Name = fmt.Sprintf("%s-%d", cfg.Type, index)
+	}
+	level := zapcore.DebugLevel
+	if cfg.Level != "" {
+		var err error
+		if level, err = logging.ParseLevel(cfg.Level); err != nil {
+			return nil, fmt.Errorf("日志输出 %s: %v", cfg.Name, err)
+		}
+	}
+
+	switch cfg.Type {
+	case "stdout":
+		enc := newEncoder(cfg.Encoding, encoderConfig)
+		return &Sink{Name: cfg.Name, Core: zapcore.NewCore(enc, zapcore.Lock(os.Stdout), level)}, nil
+	case "syslog":
+		return newSyslogSink(cfg, level, encoderConfig)
+	case "http":
+		return newHTTPSink(cfg, level, encoderConfig)
+	default:
+		return nil, fmt.Errorf("日志输出 %s: 不支持的类型 %q", cfg.Name, cfg.Type)
+	}
+}
+
+// newEncoder 创建日志内容的编码器：json（默认）、console 或 journald
+func newEncoder(encoding string, encoderConfig zapcore.EncoderConfig) zapcore.Encoder {
+	switch encoding {
+	case "console":
+		return zapcore.NewConsoleEncoder(encoderConfig)
+	case "journald":
+		// journald 自行记录时间
+		encoderConfig.TimeKey = ""
+		return journaldEncoder{zapcore.NewConsoleEncoder(encoderConfig)}
+	default:
+		return zapcore.NewJSONEncoder(encoderConfig)
+	}
+}
+
+// severity 返回级别对应的 syslog 严重程度
+func severity(level zapcore.Level) int {
+	switch level {
+	case zapcore.DebugLevel:
+		return 7
+	case zapcore.InfoLevel:
+		return 6
+	case zapcore.WarnLevel:
+		return 4
+	case zapcore.ErrorLevel:
+		return 3
+	default:
+		return 2
+	}
+}
+
+var pool = buffer.NewPool()
+
+// journaldEncoder 在每行前加上 <N> 级别前缀，systemd 采集标准输出时据此识别日志级别
+type journaldEncoder struct {
+	zapcore.Encoder
+}
+
+func (e journaldEncoder) Clone() zapcore.Encoder {
+	return journaldEncoder{e.Encoder.Clone()}
+}
+
+func (e journaldEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	line, err := e.Encoder.EncodeEntry(ent, fields)
+	if err != nil {
+		return nil, err
+	}
+	defer line.Free()
+
+	prefix := []byte("<" + strconv.Itoa(severity(ent.Level)) + ">")
+	content := bytes.TrimSuffix(line.Bytes(), []byte("\n"))
+	buf := pool.Get()
+	buf.Write(prefix)
+	// 调用栈等多行内容每行都需要前缀
+	buf.Write(bytes.ReplaceAll(content, []byte("\n"), append([]byte("\n"), prefix...)))
+	buf.AppendByte('\n')
+	return buf, nil
+}
+
+// reporter 输出本身出错时写到标准错误，不能写入日志以免循环，同一输出每分钟最多一次
+type reporter struct {
+	name string
+	mu   sync.Mutex
+	last time.Time
+}
+
+func (r *reporter) report(format string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if time.Since(r.last) < time.Minute {
+		return
+	}
+	r.last = time.Now()
+	fmt.Fprintf(os.Stderr, "%s 日志输出 %s: %s\n", r.last.Format("2006-01-02 15:04:05"), r.name, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,197 @@
+package sink
+
+import (
+	"HarborArk/config"
+	"HarborArk/internal/metrics"
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// syslogTimeout 连接与单次写入的超时
+	syslogTimeout = 5 * time.Second
+	// syslogRedialDelay 连接失败后多久再重试，期间的日志直接丢弃，避免每条日志都等待连接超时
+	syslogRedialDelay = 5 * time.Second
+	// syslogTimeLayout RFC 5424 时间格式，最多精确到微秒
+	syslogTimeLayout = "2006-01-02T15:04:05.000000Z07:00"
+)
+
+// facilities syslog 设施代码
+var facilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// newSyslogSink 创建以 RFC 5424 格式发送到 syslog 服务的输出
+func newSyslogSink(cfg config.LogSinkConfig, level zapcore.Level, encoderConfig zapcore.EncoderConfig) (*Sink, error) {
+	if cfg.Network == "" {
+		cfg.Network = "udp"
+	}
+	if cfg.Facility == "" {
+		cfg.Facility = "local0"
+	}
+	if cfg.AppName == "" {
+		cfg.AppName = "harborark"
+	}
+	facility, ok := facilities[cfg.Facility]
+	if !ok {
+		return nil, fmt.Errorf("日志输出 %s: 不支持的 facility %q", cfg.Name, cfg.Facility)
+	}
+	// 时间与级别已在 syslog 头部
+	encoderConfig.TimeKey = ""
+	encoderConfig.LevelKey = ""
+	if cfg.Encoding == "journald" {
+		cfg.Encoding = "console"
+	}
+
+	hostname, _ := os.Hostname()
+	w := &syslogWriter{network: cfg.Network, address: cfg.Address, reporter: reporter{name: cfg.Name}}
+	c := &syslogCore{
+		LevelEnabler: level,
+		enc:          newEncoder(cfg.Encoding, encoderConfig),
+		w:            w,
+		name:         cfg.Name,
+		facility:     facility,
+		header:       " " + headerField(hostname, 255) + " " + headerField(cfg.AppName, 48) + " " + strconv.Itoa(os.Getpid()) + " ",
+	}
+	return &Sink{Name: cfg.Name, Core: c, close: w.close}, nil
+}
+
+// headerField 将头部字段限制为可打印 ASCII 且不超过 max 个字符，空值使用 -
+func headerField(s string, max int) string {
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s) && len(b) < max; i++ {
+		if s[i] > 32 && s[i] < 127 {
+			b = append(b, s[i])
+		}
+	}
+	if len(b) == 0 {
+		return "-"
+	}
+	return string(b)
+}
+
+// syslogCore 将每条日志编码为一条 RFC 5424 消息，日志器名称作为 MSGID
+type syslogCore struct {
+	zapcore.LevelEnabler
+	enc      zapcore.Encoder
+	w        *syslogWriter
+	name     string
+	facility int
+	// header HOSTNAME APP-NAME PROCID 部分，前后带空格
+	header string
+}
+
+func (c *syslogCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.enc = c.enc.Clone()
+	for _, f := range fields {
+		f.AddTo(clone.enc)
+	}
+	return &clone
+}
+
+func (c *syslogCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *syslogCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	body, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	defer body.Free()
+
+	// <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+	msg := pool.Get()
+	defer msg.Free()
+	msg.AppendByte('<')
+	msg.AppendInt(int64(c.facility*8 + severity(ent.Level)))
+	msg.AppendString(">1 ")
+	msg.AppendString(ent.Time.Format(syslogTimeLayout))
+	msg.AppendString(c.header)
+	msg.AppendString(headerField(ent.LoggerName, 32))
+	msg.AppendString(" - ")
+	msg.Write(bytes.TrimRight(body.Bytes(), "\n"))
+
+	if c.w.write(msg.Bytes()) {
+		metrics.AddLogSinkEntries(c.name, "sent", 1)
+	} else {
+		metrics.AddLogSinkEntries(c.name, "dropped", 1)
+	}
+	return nil
+}
+
+func (c *syslogCore) Sync() error {
+	return nil
+}
+
+// syslogWriter 维护到 syslog 服务的连接，写入失败时重连一次
+type syslogWriter struct {
+	network, address string
+	reporter         reporter
+
+	mu      sync.Mutex
+	conn    net.Conn
+	retryAt time.Time
+}
+
+// write 发送一条消息，连接不可用时返回 false
+func (w *syslogWriter) write(msg []byte) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for attempt := 0; attempt < 2; attempt++ {
+		if w.conn == nil {
+			if time.Now().Before(w.retryAt) {
+				return false
+			}
+			conn, err := net.DialTimeout(w.network, w.address, syslogTimeout)
+			if err != nil {
+				w.retryAt = time.Now().Add(syslogRedialDelay)
+				w.reporter.report("连接 %s://%s 失败: %v", w.network, w.address, err)
+				return false
+			}
+			w.conn = conn
+		}
+
+		w.conn.SetWriteDeadline(time.Now().Add(syslogTimeout)) // nolint: errcheck
+		var err error
+		switch w.network {
+		case "tcp":
+			// RFC 6587 octet counting 分帧
+			_, err = fmt.Fprintf(w.conn, "%d %s", len(msg), msg)
+		case "unix":
+			_, err = w.conn.Write(append(msg, '\n'))
+		default:
+			_, err = w.conn.Write(msg)
+		}
+		if err == nil {
+			return true
+		}
+		w.reporter.report("发送失败: %v", err)
+		w.conn.Close()
+		w.conn = nil
+	}
+	return false
+}
+
+func (w *syslogWriter) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn != nil {
+		w.conn.Close()
+		w.conn = nil
+	}
+}
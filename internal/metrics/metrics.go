@@ -0,0 +1,207 @@
+package metrics
+
+import (
+	"HarborArk/config"
+	"HarborArk/internal/storage"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+)
+
+const namespace = "harborark"
+
+var (
+	// Registry 应用指标注册表
+	Registry = prometheus.NewRegistry()
+
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP 请求总数",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP 请求处理耗时",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"method", "route", "status"})
+
+	transferBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transfer_bytes_total",
+		Help:      "上传与下载的字节总数",
+	}, []string{"direction"})
+
+	logSinkEntries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "log_sink",
+		Name:      "entries_total",
+		Help:      "日志输出处理的日志条数，result 为 sent、dropped 或 failed",
+	}, []string{"sink", "result"})
+
+	rateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ratelimit",
+		Name:      "rejected_total",
+		Help:      "因超出限额被拒绝的请求数，scope 为 ip、user 或路由分组的路径",
+	}, []string{"scope"})
+
+	authLockouts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "lockouts_total",
+		Help:      "因连续登录失败产生的锁定次数，kind 为 ip 或 user",
+	}, []string{"kind"})
+
+	jobQueueDepth = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "job", "queue_depth"),
+		"后台任务队列中待处理的任务数",
+		[]string{"queue"}, nil,
+	)
+
+	uploadBytes   = transferBytes.WithLabelValues("upload")
+	downloadBytes = transferBytes.WithLabelValues("download")
+
+	queues = &queueCollector{depth: make(map[string]func() int)}
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(collectors.WithGoCollectorRuntimeMetrics(collectors.MetricsAll)),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpDuration,
+		transferBytes,
+		logSinkEntries,
+		rateLimited,
+		authLockouts,
+		queues,
+	)
+}
+
+// Init 注册存储卷容量指标
+func Init(volumes []config.VolumeConfig) {
+	Registry.MustRegister(&volumeCollector{volumes: volumes})
+}
+
+// Handler 指标导出处理器
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{
+		ErrorLog: zap.NewStdLog(zap.L()),
+	})
+}
+
+// ObserveRequest 记录一次 HTTP 请求，route 为路由模板
+func ObserveRequest(method, route, status string, cost time.Duration) {
+	httpRequests.WithLabelValues(method, route, status).Inc()
+	httpDuration.WithLabelValues(method, route, status).Observe(cost.Seconds())
+}
+
+// AddUploadBytes 累加上传字节数
+func AddUploadBytes(n int64) {
+	if n > 0 {
+		uploadBytes.Add(float64(n))
+	}
+}
+
+// AddDownloadBytes 累加下载字节数
+func AddDownloadBytes(n int64) {
+	if n > 0 {
+		downloadBytes.Add(float64(n))
+	}
+}
+
+// AddLogSinkEntries 累加日志输出处理的条数
+func AddLogSinkEntries(sink, result string, n int) {
+	if n > 0 {
+		logSinkEntries.WithLabelValues(sink, result).Add(float64(n))
+	}
+}
+
+// AddRateLimited 累加因超出限额被拒绝的请求数
+func AddRateLimited(scope string) {
+	rateLimited.WithLabelValues(scope).Inc()
+}
+
+// AddLockout 累加登录失败锁定次数
+func AddLockout(kind string) {
+	authLockouts.WithLabelValues(kind).Inc()
+}
+
+// RegisterQueue 注册后台任务队列，采集时调用 depth 获取队列深度
+func RegisterQueue(name string, depth func() int) {
+	queues.mu.Lock()
+	defer queues.mu.Unlock()
+	queues.depth[name] = depth
+}
+
+// queueCollector 采集时读取各后台任务队列深度
+type queueCollector struct {
+	mu    sync.Mutex
+	depth map[string]func() int
+}
+
+func (q *queueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- jobQueueDepth
+}
+
+func (q *queueCollector) Collect(ch chan<- prometheus.Metric) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for name, depth := range q.depth {
+		ch <- prometheus.MustNewConstMetric(jobQueueDepth, prometheus.GaugeValue, float64(depth()), name)
+	}
+}
+
+// volumeCollector 采集时读取存储卷容量
+type volumeCollector struct {
+	volumes []config.VolumeConfig
+}
+
+var (
+	volumeSize = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "volume", "size_bytes"),
+		"存储卷总容量", []string{"volume", "path"}, nil,
+	)
+	volumeFree = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "volume", "free_bytes"),
+		"存储卷可用容量", []string{"volume", "path"}, nil,
+	)
+	volumeUsed = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "volume", "used_bytes"),
+		"存储卷已用容量", []string{"volume", "path"}, nil,
+	)
+	volumeUp = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "volume", "up"),
+		"存储卷是否可访问", []string{"volume", "path"}, nil,
+	)
+)
+
+func (v *volumeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- volumeSize
+	ch <- volumeFree
+	ch <- volumeUsed
+	ch <- volumeUp
+}
+
+func (v *volumeCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, vol := range v.volumes {
+		usage, err := storage.DiskUsage(vol.Path)
+		if err != nil {
+			ch <- prometheus.MustNewConstMetric(volumeUp, prometheus.GaugeValue, 0, vol.Name, vol.Path)
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(volumeUp, prometheus.GaugeValue, 1, vol.Name, vol.Path)
+		ch <- prometheus.MustNewConstMetric(volumeSize, prometheus.GaugeValue, float64(usage.Total), vol.Name, vol.Path)
+		ch <- prometheus.MustNewConstMetric(volumeFree, prometheus.GaugeValue, float64(usage.Free), vol.Name, vol.Path)
+		ch <- prometheus.MustNewConstMetric(volumeUsed, prometheus.GaugeValue, float64(usage.Used), vol.Name, vol.Path)
+	}
+}
@@ -0,0 +1,175 @@
+package ratelimit
+
+import (
+	"HarborArk/config"
+	"HarborArk/internal/i18n"
+	"fmt"
+	"math"
+	"net"
+	"sync"
+	"time"
+)
+
+// Result 一次限流判断的结果，用于生成 RateLimit-* 响应头
+type Result struct {
+	Allowed bool
+	// Limit 每个周期允许的请求数，与 RateLimit-Policy 中的限额一致
+	Limit int
+	// Remaining 本次请求之后剩余的令牌数，不超过桶容量 burst
+	Remaining int
+	// Reset 令牌补满所需的时间
+	Reset time.Duration
+	// RetryAfter 被拒绝时下一个令牌可用前需等待的时间
+	RetryAfter time.Duration
+}
+
+// bucket 单个键的令牌桶
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Limiter 按键分别计数的令牌桶限流器，可并发使用
+type Limiter struct {
+	rate     float64 // 每秒补充的令牌数
+	capacity float64
+	period   time.Duration
+	limit    int
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewLimiter 创建限流器，rule.Limit 为 0 时返回 nil，表示不限制
+func NewLimiter(rule config.RateLimitRule) *Limiter {
+	if rule.Limit <= 0 || rule.Period <= 0 {
+		return nil
+	}
+	burst := rule.Burst
+	if burst <= 0 {
+		burst = rule.Limit
+	}
+	return &Limiter{
+		rate:     float64(rule.Limit) / rule.Period.Seconds(),
+		capacity: float64(burst),
+		period:   rule.Period,
+		limit:    rule.Limit,
+		buckets:  make(map[string]*bucket),
+	}
+}
+
+// Policy 返回 RateLimit-Policy 响应头中的限额说明，如 300;w=60;burst=60
+func (l *Limiter) Policy() string {
+	return fmt.Sprintf("%d;w=%d;burst=%d", l.limit, int(math.Ceil(l.period.Seconds())), int(l.capacity))
+}
+
+// Allow 为 key 消耗一个令牌，令牌不足时拒绝
+func (l *Limiter) Allow(key string) Result {
+	return l.allowAt(key, time.Now())
+}
+
+func (l *Limiter) allowAt(key string, now time.Time) Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.capacity, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(l.capacity, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	res := Result{Limit: l.limit}
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = l.wait(1 - b.tokens)
+	}
+	res.Remaining = int(b.tokens)
+	res.Reset = l.wait(l.capacity - b.tokens)
+	return res
+}
+
+// wait 返回补充 n 个令牌所需的时间
+func (l *Limiter) wait(n float64) time.Duration {
+	if n <= 0 {
+		return 0
+	}
+	return time.Duration(n / l.rate * float64(time.Second))
+}
+
+// sweep 每个周期清理一次已补满的令牌桶，避免大量一次性客户端占用内存
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.period {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.capacity {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Len 返回正在计数的键数量
+func (l *Limiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.buckets)
+}
+
+// IPKey 返回客户端 IP 的计数键。IPv6 客户端通常可以自由使用整个 /64 网段，因此按网段计数
+func IPKey(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return "ip:" + ip
+	}
+	if v4 := parsed.To4(); v4 != nil {
+		return "ip:" + v4.String()
+	}
+	return "ip:" + parsed.Mask(net.CIDRMask(64, 128)).String() + "/64"
+}
+
+// UserKey 返回用户的计数键
+func UserKey(user string) string {
+	return "user:" + user
+}
+
+// ParseNetworks 解析 IP 或网段列表，单个 IP 视为 /32 或 /128
+func ParseNetworks(list []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(list))
+	for _, item := range list {
+		if _, network, err := net.ParseCIDR(item); err == nil {
+			networks = append(networks, network)
+			continue
+		}
+		ip := net.ParseIP(item)
+		if ip == nil {
+			return nil, i18n.NewError("errors.ratelimit.invalid_network", item)
+		}
+		bits := 128
+		if v4 := ip.To4(); v4 != nil {
+			ip, bits = v4, 32
+		}
+		networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+	}
+	return networks, nil
+}
+
+// Contains 判断 ip 是否属于任一网段
+func Contains(networks []*net.IPNet, ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range networks {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package ratelimit
+
+import (
+	"HarborArk/config"
+	"testing"
+	"time"
+)
+
+func TestNewLimiter(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    config.RateLimitRule
+		wantNil bool
+		policy  string
+	}{
+		{"unlimited", config.RateLimitRule{Limit: 0, Period: time.Minute}, true, ""},
+		{"no period", config.RateLimitRule{Limit: 10}, true, ""},
+		{"burst defaults to limit", config.RateLimitRule{Limit: 300, Period: time.Minute}, false, "300;w=60;burst=300"},
+		{"burst", config.RateLimitRule{Limit: 300, Period: time.Minute, Burst: 60}, false, "300;w=60;burst=60"},
+		{"sub-second period", config.RateLimitRule{Limit: 5, Period: 500 * time.Millisecond}, false, "5;w=1;burst=5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLimiter(tt.rule)
+			if (l == nil) != tt.wantNil {
+				t.Fatalf("NewLimiter() = %v, want nil %v", l, tt.wantNil)
+			}
+			if l != nil && l.Policy() != tt.policy {
+				t.Errorf("Policy() = %q, want %q", l.Policy(), tt.policy)
+			}
+		})
+	}
+}
+
+func TestAllowAt(t *testing.T) {
+	// 每分钟 60 个，即每秒补充 1 个令牌，最多突发 3 个
+	l := NewLimiter(config.RateLimitRule{Limit: 60, Period: time.Minute, Burst: 3})
+	start := time.Unix(1_700_000_000, 0)
+
+	steps := []struct {
+		name      string
+		key       string
+		after     time.Duration
+		allowed   bool
+		remaining int
+		reset     time.Duration
+		retry     time.Duration
+	}{
+		{"first", "a", 0, true, 2, time.Second, 0},
+		{"second", "a", 0, true, 1, 2 * time.Second, 0},
+		{"burst used up", "a", 0, true, 0, 3 * time.Second, 0},
+		{"rejected", "a", 0, false, 0, 3 * time.Second, time.Second},
+		{"partly refilled", "a", 500 * time.Millisecond, false, 0, 2500 * time.Millisecond, 500 * time.Millisecond},
+		{"refilled one", "a", time.Second, true, 0, 2500 * time.Millisecond, 0},
+		{"other key has own bucket", "b", 1500 * time.Millisecond, true, 2, time.Second, 0},
+		{"capped at burst", "a", time.Minute, true, 2, time.Second, 0},
+	}
+	now := start
+	for _, step := range steps {
+		now = now.Add(step.after)
+		res := l.allowAt(step.key, now)
+		if res.Allowed != step.allowed || res.Remaining != step.remaining || res.Reset != step.reset || res.RetryAfter != step.retry {
+			t.Errorf("%s: allowAt() = %+v, want allowed=%v remaining=%d reset=%v retry=%v",
+				step.name, res, step.allowed, step.remaining, step.reset, step.retry)
+		}
+		// RateLimit-Limit 与 RateLimit-Policy 使用同一限额
+		if res.Limit != 60 {
+			t.Errorf("%s: Limit = %d, want 60", step.name, res.Limit)
+		}
+	}
+}
+
+func TestSweep(t *testing.T) {
+	// 每秒补充 1 个令牌，桶容量 3
+	l := NewLimiter(config.RateLimitRule{Limit: 1, Period: time.Second, Burst: 3})
+	now := time.Unix(1_700_000_000, 0)
+	l.allowAt("a", now)
+	for i := 0; i < 3; i++ {
+		l.allowAt("b", now)
+	}
+
+	// 不到一个周期时不清理
+	l.allowAt("c", now.Add(500*time.Millisecond))
+	if got := l.Len(); got != 3 {
+		t.Fatalf("Len() = %d, want 3", got)
+	}
+	// 一个周期后 a 与 c 已补满被清理，b 仍在补充中
+	l.allowAt("b", now.Add(1500*time.Millisecond))
+	if got := l.Len(); got != 1 {
+		t.Errorf("Len() = %d, want 1", got)
+	}
+}
+
+func TestIPKey(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want string
+	}{
+		{"203.0.113.7", "ip:203.0.113.7"},
+		{"::ffff:203.0.113.7", "ip:203.0.113.7"},
+		{"2001:db8:1:2:3:4:5:6", "ip:2001:db8:1:2::/64"},
+		{"2001:db8:1:2::9", "ip:2001:db8:1:2::/64"},
+		{"not-an-ip", "ip:not-an-ip"},
+	}
+	for _, tt := range tests {
+		if got := IPKey(tt.ip); got != tt.want {
+			t.Errorf("IPKey(%q) = %q, want %q", tt.ip, got, tt.want)
+		}
+	}
+}
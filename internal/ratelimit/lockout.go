@@ -0,0 +1,161 @@
+package ratelimit
+
+import (
+	"HarborArk/config"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// failures 单个键的登录失败记录
+type failures struct {
+	count       int
+	lastFailure time.Time
+	lockedUntil time.Time
+	// level 已锁定的次数，决定下一次锁定的时长
+	level int
+}
+
+// LockedKey 处于锁定状态的键
+type LockedKey struct {
+	// Key 计数键，如 user:alice、ip:203.0.113.7
+	Key string `json:"key" example:"user:alice"`
+	// Lockouts 已锁定的次数，下一次锁定时长按此加倍
+	Lockouts    int       `json:"lockouts" example:"1"`
+	LockedUntil time.Time `json:"lockedUntil"`
+}
+
+// Lockout 登录失败锁定：同一键在统计窗口内失败 MaxFailures 次后锁定，
+// 每次锁定的时长为上一次的两倍，不超过 MaxDuration。登录成功后清除记录
+type Lockout struct {
+	mu        sync.Mutex
+	cfg       config.LockoutConfig
+	entries   map[string]*failures
+	lastSweep time.Time
+}
+
+// NewLockout 创建登录失败锁定
+func NewLockout(cfg config.LockoutConfig) *Lockout {
+	return &Lockout{cfg: cfg, entries: make(map[string]*failures)}
+}
+
+var defaultLockout = NewLockout(config.Default().RateLimit.Lockout)
+
+// Lockouts 返回全局的登录失败锁定
+func Lockouts() *Lockout {
+	return defaultLockout
+}
+
+// Configure 更新锁定参数，已有的失败记录与锁定保持不变
+func (l *Lockout) Configure(cfg config.LockoutConfig) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.cfg = cfg
+}
+
+// Locked 返回各键中最长的剩余锁定时间，均未锁定或未启用锁定时返回 0
+func (l *Lockout) Locked(keys ...string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.cfg.Enabled {
+		return 0
+	}
+	now := time.Now()
+	var wait time.Duration
+	for _, key := range keys {
+		if f, ok := l.entries[key]; ok {
+			wait = max(wait, f.lockedUntil.Sub(now))
+		}
+	}
+	return wait
+}
+
+// Fail 为各键记录一次登录失败，返回因本次失败而被锁定的键
+func (l *Lockout) Fail(keys ...string) []LockedKey {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.cfg.Enabled {
+		return nil
+	}
+	var locked []LockedKey
+	now := time.Now()
+	l.sweep(now)
+	for _, key := range keys {
+		f, ok := l.entries[key]
+		if !ok {
+			f = &failures{}
+			l.entries[key] = f
+		}
+		if now.Sub(f.lastFailure) > l.cfg.Window {
+			f.count = 0
+		}
+		f.count++
+		f.lastFailure = now
+		if f.count < l.cfg.MaxFailures {
+			continue
+		}
+		f.lockedUntil = now.Add(l.duration(f.level))
+		f.level++
+		f.count = 0
+		locked = append(locked, LockedKey{Key: key, Lockouts: f.level, LockedUntil: f.lockedUntil})
+	}
+	return locked
+}
+
+// duration 返回第 level+1 次锁定的时长
+func (l *Lockout) duration(level int) time.Duration {
+	d := l.cfg.Duration
+	for i := 0; i < level && d < l.cfg.MaxDuration; i++ {
+		d *= 2
+	}
+	return min(d, l.cfg.MaxDuration)
+}
+
+// Succeed 登录成功，清除各键的失败记录
+func (l *Lockout) Succeed(keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, key := range keys {
+		delete(l.entries, key)
+	}
+}
+
+// Unlock 解除锁定并清除失败记录，键不存在时返回 false
+func (l *Lockout) Unlock(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, ok := l.entries[key]
+	delete(l.entries, key)
+	return ok
+}
+
+// List 返回处于锁定状态的键，按解锁时间排序；prefix 非空时只返回以其开头的键
+func (l *Lockout) List(prefix string) []LockedKey {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	list := []LockedKey{}
+	for key, f := range l.entries {
+		if f.lockedUntil.After(now) && strings.HasPrefix(key, prefix) {
+			list = append(list, LockedKey{Key: key, Lockouts: f.level, LockedUntil: f.lockedUntil})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].LockedUntil.Before(list[j].LockedUntil) })
+	return list
+}
+
+// sweep 清理长时间没有失败且未锁定的记录。锁定次数在此之前保留，
+// 使锁定结束后立即再次失败的键获得更长的锁定
+func (l *Lockout) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.cfg.Window {
+		return
+	}
+	l.lastSweep = now
+	idle := max(l.cfg.Window, l.cfg.MaxDuration)
+	for key, f := range l.entries {
+		if now.Sub(f.lastFailure) > idle && now.After(f.lockedUntil) {
+			delete(l.entries, key)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package ratelimit
+
+import (
+	"HarborArk/config"
+	"testing"
+	"time"
+)
+
+// newLockout 创建 3 次失败锁定 1 分钟、最长 10 分钟的锁定
+func newLockout() *Lockout {
+	return NewLockout(config.LockoutConfig{
+		Enabled:     true,
+		MaxFailures: 3,
+		Window:      15 * time.Minute,
+		Duration:    time.Minute,
+		MaxDuration: 10 * time.Minute,
+	})
+}
+
+func TestLockoutDuration(t *testing.T) {
+	l := newLockout()
+	tests := []struct {
+		level int
+		want  time.Duration
+	}{
+		{0, time.Minute},
+		{1, 2 * time.Minute},
+		{2, 4 * time.Minute},
+		{3, 8 * time.Minute},
+		{4, 10 * time.Minute},
+		{50, 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := l.duration(tt.level); got != tt.want {
+			t.Errorf("duration(%d) = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+
+	// 首次锁定时长已超过上限时使用上限
+	l.Configure(config.LockoutConfig{Enabled: true, Duration: time.Hour, MaxDuration: 30 * time.Minute})
+	if got := l.duration(0); got != 30*time.Minute {
+		t.Errorf("duration(0) = %v, want 30m", got)
+	}
+}
+
+func TestLockoutFail(t *testing.T) {
+	l := newLockout()
+	keys := []string{UserKey("alice"), IPKey("203.0.113.7")}
+
+	for i := 1; i < 3; i++ {
+		if locked := l.Fail(keys...); len(locked) != 0 {
+			t.Fatalf("第 %d 次失败不应锁定: %v", i, locked)
+		}
+	}
+	if wait := l.Locked(keys...); wait != 0 {
+		t.Fatalf("Locked() = %v, want 0", wait)
+	}
+
+	locked := l.Fail(keys...)
+	if len(locked) != 2 || locked[0].Lockouts != 1 {
+		t.Fatalf("第 3 次失败应锁定两个键: %v", locked)
+	}
+	if wait := l.Locked(UserKey("alice")); wait <= 50*time.Second || wait > time.Minute {
+		t.Errorf("Locked() = %v, want about 1m", wait)
+	}
+	if wait := l.Locked(UserKey("bob")); wait != 0 {
+		t.Errorf("其他用户 Locked() = %v, want 0", wait)
+	}
+	if got := l.List("user:"); len(got) != 1 || got[0].Key != UserKey("alice") {
+		t.Errorf("List(user:) = %v", got)
+	}
+
+	// 再次锁定时长加倍
+	for i := 0; i < 3; i++ {
+		locked = l.Fail(keys[0])
+	}
+	if len(locked) != 1 || locked[0].Lockouts != 2 {
+		t.Fatalf("再次锁定 = %v", locked)
+	}
+	if wait := l.Locked(keys[0]); wait <= 110*time.Second || wait > 2*time.Minute {
+		t.Errorf("Locked() = %v, want about 2m", wait)
+	}
+
+	// 登录成功清除记录，解除锁定只影响指定的键
+	l.Succeed(keys[0])
+	if wait := l.Locked(keys[0]); wait != 0 {
+		t.Errorf("Succeed() 后 Locked() = %v, want 0", wait)
+	}
+	if !l.Unlock(keys[1]) || l.Unlock(keys[1]) {
+		t.Error("Unlock() 应只在键存在时返回 true")
+	}
+}
+
+func TestLockoutWindow(t *testing.T) {
+	l := newLockout()
+	key := UserKey("alice")
+	l.Fail(key)
+	l.Fail(key)
+
+	// 超出统计窗口的失败不再计数
+	l.entries[key].lastFailure = time.Now().Add(-16 * time.Minute)
+	if locked := l.Fail(key); len(locked) != 0 {
+		t.Errorf("窗口外的失败不应累计: %v", locked)
+	}
+	if got := l.entries[key].count; got != 1 {
+		t.Errorf("count = %d, want 1", got)
+	}
+}
+
+func TestLockoutDisabled(t *testing.T) {
+	l := NewLockout(config.LockoutConfig{Enabled: false, MaxFailures: 1, Duration: time.Minute, MaxDuration: time.Minute})
+	if locked := l.Fail(UserKey("alice")); locked != nil {
+		t.Errorf("未启用时 Fail() = %v", locked)
+	}
+	if wait := l.Locked(UserKey("alice")); wait != 0 {
+		t.Errorf("未启用时 Locked() = %v", wait)
+	}
+}
@@ -0,0 +1,121 @@
+package response
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// 机器可读的错误码，客户端应据此而不是 message 判断错误类型
+const (
+	ReasonBadRequest       = "BAD_REQUEST"
+	ReasonValidation       = "VALIDATION_FAILED"
+	ReasonUnauthorized     = "UNAUTHORIZED"
+	ReasonForbidden        = "FORBIDDEN"
+	ReasonCSRF             = "CSRF_TOKEN_INVALID"
+	ReasonNotFound         = "NOT_FOUND"
+	ReasonMethodNotAllowed = "METHOD_NOT_ALLOWED"
+	ReasonConflict         = "CONFLICT"
+	ReasonTooManyRequests  = "TOO_MANY_REQUESTS"
+	ReasonInternal         = "INTERNAL_ERROR"
+	ReasonUnavailable      = "SERVICE_UNAVAILABLE"
+)
+
+// Error 应用错误，携带对应的 HTTP 状态码与错误码，由 Fail 写入响应
+type Error struct {
+	Status  int
+	Reason  string
+	Message string
+	// Detail 补充说明，如参数解析失败的原因，响应中为 error 字段
+	Detail string
+	// Data 随错误返回的数据
+	Data interface{}
+	// cause 原始错误，只记录到日志，不返回给客户端
+	cause error
+}
+
+// New 创建应用错误
+func New(status int, reason, message string) *Error {
+	return &Error{Status: status, Reason: reason, Message: message}
+}
+
+// BadRequest 请求参数错误 (400)
+func BadRequest(message string) *Error {
+	return New(http.StatusBadRequest, ReasonBadRequest, message)
+}
+
+// Validation 请求参数未通过校验 (400)，逐字段的错误通过 WithData 返回
+func Validation(message string) *Error {
+	return New(http.StatusBadRequest, ReasonValidation, message)
+}
+
+// Unauthorized 未认证 (401)
+func Unauthorized(message string) *Error {
+	return New(http.StatusUnauthorized, ReasonUnauthorized, message)
+}
+
+// Forbidden 无权访问 (403)
+func Forbidden(message string) *Error {
+	return New(http.StatusForbidden, ReasonForbidden, message)
+}
+
+// NotFound 资源不存在 (404)
+func NotFound(message string) *Error {
+	return New(http.StatusNotFound, ReasonNotFound, message)
+}
+
+// MethodNotAllowed 不支持的请求方法 (405)
+func MethodNotAllowed(message string) *Error {
+	return New(http.StatusMethodNotAllowed, ReasonMethodNotAllowed, message)
+}
+
+// Conflict 与当前状态冲突 (409)
+func Conflict(message string) *Error {
+	return New(http.StatusConflict, ReasonConflict, message)
+}
+
+// TooManyRequests 请求过于频繁或已被锁定 (429)，Retry-After 响应头由调用方设置
+func TooManyRequests(message string) *Error {
+	return New(http.StatusTooManyRequests, ReasonTooManyRequests, message)
+}
+
+// Internal 服务器内部错误 (500)
+func Internal(message string) *Error {
+	return New(http.StatusInternalServerError, ReasonInternal, message)
+}
+
+// Unavailable 功能未启用或暂不可用 (503)
+func Unavailable(message string) *Error {
+	return New(http.StatusServiceUnavailable, ReasonUnavailable, message)
+}
+
+// WithDetail 返回附加了补充说明的副本
+func (e *Error) WithDetail(detail string) *Error {
+	clone := *e
+	clone.Detail = detail
+	return &clone
+}
+
+// WithData 返回附加了数据的副本
+func (e *Error) WithData(data interface{}) *Error {
+	clone := *e
+	clone.Data = data
+	return &clone
+}
+
+// WithCause 返回记录了原始错误的副本，原始错误只写入日志
+func (e *Error) WithCause(err error) *Error {
+	clone := *e
+	clone.cause = err
+	return &clone
+}
+
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.cause)
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
@@ -0,0 +1,124 @@
+package response
+
+import (
+	"HarborArk/internal/i18n"
+	"HarborArk/internal/logging"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// MIMEProblem RFC 7807 错误响应的类型，请求头 Accept 中包含时以此格式返回错误
+const MIMEProblem = "application/problem+json"
+
+// problemTypePrefix 错误类型 URI 的前缀，后接错误码
+const problemTypePrefix = "urn:harborark:error:"
+
+// Body 成功响应
+type Body struct {
+	Code    int         `json:"code" example:"200"`
+	Message string      `json:"message" example:"获取成功"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// PageBody 分页查询的成功响应
+type PageBody struct {
+	Body
+	// Total 匹配的总数
+	Total int `json:"total" example:"120"`
+}
+
+// ErrorBody 错误响应
+type ErrorBody struct {
+	// Code HTTP 状态码
+	Code int `json:"code" example:"404"`
+	// Reason 机器可读的错误码
+	Reason  string `json:"reason" example:"NOT_FOUND"`
+	Message string `json:"message" example:"用户不存在"`
+	// Error 补充说明，如参数解析失败的原因
+	Error     string      `json:"error,omitempty" example:"strconv.Atoi: parsing \"abc\": invalid syntax"`
+	RequestID string      `json:"request_id,omitempty" example:"4f3c2a1b9d8e7f60a1b2c3d4e5f60718"`
+	Data      interface{} `json:"data,omitempty"`
+}
+
+// Problem RFC 7807 格式的错误响应，请求头 Accept 包含 application/problem+json 时使用
+type Problem struct {
+	Type   string `json:"type" example:"urn:harborark:error:NOT_FOUND"`
+	Title  string `json:"title" example:"Not Found"`
+	Status int    `json:"status" example:"404"`
+	// Detail 错误说明，对应 ErrorBody 的 message
+	Detail   string `json:"detail" example:"用户不存在"`
+	Instance string `json:"instance" example:"/api/v1/users/9"`
+	// Reason 机器可读的错误码
+	Reason    string      `json:"reason" example:"NOT_FOUND"`
+	Error     string      `json:"error,omitempty"`
+	RequestID string      `json:"request_id,omitempty" example:"4f3c2a1b9d8e7f60a1b2c3d4e5f60718"`
+	Data      interface{} `json:"data,omitempty"`
+}
+
+// Success 以指定状态码返回成功响应
+func Success(c *gin.Context, status int, data interface{}, message string) {
+	c.JSON(status, Body{Code: status, Message: message, Data: data})
+}
+
+// OK 返回 200 成功响应
+func OK(c *gin.Context, data interface{}, message string) {
+	Success(c, http.StatusOK, data, message)
+}
+
+// Created 返回 201 成功响应
+func Created(c *gin.Context, data interface{}, message string) {
+	Success(c, http.StatusCreated, data, message)
+}
+
+// Accepted 返回 202 成功响应
+func Accepted(c *gin.Context, data interface{}, message string) {
+	Success(c, http.StatusAccepted, data, message)
+}
+
+// Page 返回分页查询结果
+func Page(c *gin.Context, data interface{}, total int, message string) {
+	c.JSON(http.StatusOK, PageBody{
+		Body:  Body{Code: http.StatusOK, Message: message, Data: data},
+		Total: total,
+	})
+}
+
+// Fail 返回错误响应并中止后续处理。err 不是 *Error 时按 500 处理，
+// 5xx 错误连同原始错误写入日志，原始错误不返回给客户端
+func Fail(c *gin.Context, err error) {
+	var e *Error
+	if !errors.As(err, &e) {
+		e = Internal(i18n.Ctx(c.Request.Context()).T("api.error.internal")).WithCause(err)
+	}
+	if e.Status >= http.StatusInternalServerError && e.cause != nil {
+		logging.Ctx(c.Request.Context()).Error(e.Message, zap.Int("status", e.Status), zap.Error(e.cause))
+	}
+
+	requestID := logging.RequestID(c.Request.Context())
+	if c.NegotiateFormat(gin.MIMEJSON, MIMEProblem) == MIMEProblem {
+		c.Header("Content-Type", MIMEProblem)
+		c.AbortWithStatusJSON(e.Status, Problem{
+			Type:      problemTypePrefix + e.Reason,
+			Title:     http.StatusText(e.Status),
+			Status:    e.Status,
+			Detail:    e.Message,
+			Instance:  c.Request.URL.Path,
+			Reason:    e.Reason,
+			Error:     e.Detail,
+			RequestID: requestID,
+			Data:      e.Data,
+		})
+		return
+	}
+	c.AbortWithStatusJSON(e.Status, ErrorBody{
+		Code:      e.Status,
+		Reason:    e.Reason,
+		Message:   e.Message,
+		Error:     e.Detail,
+		RequestID: requestID,
+		Data:      e.Data,
+	})
+}
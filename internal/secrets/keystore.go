@@ -0,0 +1,295 @@
+package secrets
+
+import (
+	"HarborArk/internal/i18n"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// 主密钥来源
+const (
+	// EnvMasterKey 直接提供主密钥的环境变量
+	EnvMasterKey = "HARBORARK_MASTER_KEY"
+	// EnvMasterKeyFile 指定主密钥文件的环境变量
+	EnvMasterKeyFile = "HARBORARK_MASTER_KEY_FILE"
+)
+
+// 用于在打开密钥库时确认主密钥正确的校验数据，名称不会与合法的密钥名称冲突
+const (
+	checkPlaintext = "harborark-keystore"
+	checkName      = "\x00check"
+)
+
+// ErrNotFound 密钥库中不存在该条目
+var ErrNotFound = i18n.NewError("errors.secrets.not_found")
+
+// namePattern 合法的密钥名称
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// kdfParams argon2id 参数，随密钥库保存以便日后调整
+type kdfParams struct {
+	Salt    []byte `json:"salt"`
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory"`
+	Threads uint8  `json:"threads"`
+}
+
+// entry 一个加密后的密钥
+type entry struct {
+	Value     []byte    `json:"value"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// keystoreFile 密钥库文件格式
+type keystoreFile struct {
+	Version int              `json:"version"`
+	KDF     kdfParams        `json:"kdf"`
+	Check   []byte           `json:"check"`
+	Secrets map[string]entry `json:"secrets"`
+}
+
+// Entry 密钥的元信息，不包含值
+type Entry struct {
+	Name      string    `json:"name"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Keystore 使用主密钥派生的 AES-256-GCM 密钥加密保存的本地密钥库
+type Keystore struct {
+	path string
+	aead cipher.AEAD
+	file keystoreFile
+}
+
+// MasterKey 按 HARBORARK_MASTER_KEY、HARBORARK_MASTER_KEY_FILE、keyFile 的顺序读取主密钥，
+// 同时返回主密钥的来源说明
+func MasterKey(keyFile string) ([]byte, string, error) {
+	if key := os.Getenv(EnvMasterKey); key != "" {
+		return []byte(key), i18n.T("cli.secrets.source.env", EnvMasterKey), nil
+	}
+	source := i18n.T("cli.secrets.source.file", keyFile)
+	if file := os.Getenv(EnvMasterKeyFile); file != "" {
+		keyFile = file
+		source = i18n.T("cli.secrets.source.env_file", EnvMasterKeyFile, file)
+	}
+	if keyFile == "" {
+		return nil, "", i18n.NewError("errors.secrets.no_master_key", EnvMasterKey)
+	}
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, "", i18n.NewError("errors.secrets.read_master_key_failed", err)
+	}
+	key := []byte(strings.TrimRight(string(data), "\r\n"))
+	if len(key) == 0 {
+		return nil, "", i18n.NewError("errors.secrets.empty_master_key", keyFile)
+	}
+	return key, source, nil
+}
+
+// GenerateKey 生成随机主密钥
+func GenerateKey() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", i18n.NewError("errors.secrets.generate_key_failed", err)
+	}
+	return base64.StdEncoding.EncodeToString(buf), nil
+}
+
+// Open 打开已有的密钥库，主密钥错误时返回错误
+func Open(path string, masterKey []byte) (*Keystore, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, i18n.NewError("errors.secrets.read_failed", err)
+	}
+	ks := &Keystore{path: path}
+	if err := json.Unmarshal(data, &ks.file); err != nil {
+		return nil, i18n.NewError("errors.secrets.parse_failed", err)
+	}
+	if ks.file.Version != 1 {
+		return nil, i18n.NewError("errors.secrets.unsupported_version", ks.file.Version)
+	}
+	if ks.aead, err = deriveAEAD(masterKey, ks.file.KDF); err != nil {
+		return nil, err
+	}
+	if plain, err := ks.open(ks.file.Check, checkName); err != nil || plain != checkPlaintext {
+		return nil, i18n.NewError("errors.secrets.wrong_key")
+	}
+	if ks.file.Secrets == nil {
+		ks.file.Secrets = map[string]entry{}
+	}
+	return ks, nil
+}
+
+// OpenOrCreate 打开密钥库，文件不存在时使用 masterKey 创建新的密钥库
+func OpenOrCreate(path string, masterKey []byte) (*Keystore, error) {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		ks := &Keystore{path: path}
+		if err := ks.init(masterKey); err != nil {
+			return nil, err
+		}
+		return ks, nil
+	}
+	return Open(path, masterKey)
+}
+
+// Get 解密并返回密钥
+func (k *Keystore) Get(name string) (string, error) {
+	e, ok := k.file.Secrets[name]
+	if !ok {
+		return "", i18n.NewError("errors.secrets.not_found_name", ErrNotFound, name)
+	}
+	value, err := k.open(e.Value, name)
+	if err != nil {
+		return "", i18n.NewError("errors.secrets.decrypt_failed", name, err)
+	}
+	return value, nil
+}
+
+// Set 加密保存密钥
+func (k *Keystore) Set(name, value string) error {
+	if !namePattern.MatchString(name) {
+		return i18n.NewError("errors.secrets.invalid_name", name)
+	}
+	sealed, err := k.seal(value, name)
+	if err != nil {
+		return err
+	}
+	k.file.Secrets[name] = entry{Value: sealed, UpdatedAt: time.Now()}
+	return k.save()
+}
+
+// Delete 删除密钥
+func (k *Keystore) Delete(name string) error {
+	if _, ok := k.file.Secrets[name]; !ok {
+		return i18n.NewError("errors.secrets.not_found_name", ErrNotFound, name)
+	}
+	delete(k.file.Secrets, name)
+	return k.save()
+}
+
+// List 返回按名称排序的密钥列表
+func (k *Keystore) List() []Entry {
+	entries := make([]Entry, 0, len(k.file.Secrets))
+	for name, e := range k.file.Secrets {
+		entries = append(entries, Entry{Name: name, UpdatedAt: e.UpdatedAt})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+	return entries
+}
+
+// Rotate 使用新的主密钥与盐重新加密全部密钥
+func (k *Keystore) Rotate(newMasterKey []byte) error {
+	values := make(map[string]string, len(k.file.Secrets))
+	for name := range k.file.Secrets {
+		value, err := k.Get(name)
+		if err != nil {
+			return err
+		}
+		values[name] = value
+	}
+
+	old := k.file
+	rotated := &Keystore{path: k.path, file: keystoreFile{Secrets: map[string]entry{}}}
+	if err := rotated.setup(newMasterKey); err != nil {
+		return err
+	}
+	for name, value := range values {
+		sealed, err := rotated.seal(value, name)
+		if err != nil {
+			return err
+		}
+		rotated.file.Secrets[name] = entry{Value: sealed, UpdatedAt: old.Secrets[name].UpdatedAt}
+	}
+	if err := rotated.save(); err != nil {
+		return err
+	}
+	*k = *rotated
+	return nil
+}
+
+// init 创建空的密钥库并写入文件
+func (k *Keystore) init(masterKey []byte) error {
+	k.file = keystoreFile{Secrets: map[string]entry{}}
+	if err := k.setup(masterKey); err != nil {
+		return err
+	}
+	return k.save()
+}
+
+// setup 生成新的盐并派生加密密钥
+func (k *Keystore) setup(masterKey []byte) error {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	k.file.Version = 1
+	k.file.KDF = kdfParams{Salt: salt, Time: 1, Memory: 64 * 1024, Threads: 4}
+	aead, err := deriveAEAD(masterKey, k.file.KDF)
+	if err != nil {
+		return err
+	}
+	k.aead = aead
+	k.file.Check, err = k.seal(checkPlaintext, checkName)
+	return err
+}
+
+// save 先写临时文件再重命名，避免写入中断损坏密钥库
+func (k *Keystore) save() error {
+	data, err := json.MarshalIndent(k.file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(k.path), 0755); err != nil {
+		return i18n.NewError("errors.secrets.mkdir_failed", err)
+	}
+	tmp := k.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return i18n.NewError("errors.secrets.write_failed", err)
+	}
+	return os.Rename(tmp, k.path)
+}
+
+// seal 加密 value，以名称作为附加数据，防止密文被挪用到其他条目
+func (k *Keystore) seal(value, name string) ([]byte, error) {
+	nonce := make([]byte, k.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return k.aead.Seal(nonce, nonce, []byte(value), []byte(name)), nil
+}
+
+func (k *Keystore) open(sealed []byte, name string) (string, error) {
+	size := k.aead.NonceSize()
+	if len(sealed) < size {
+		return "", i18n.NewError("errors.secrets.bad_ciphertext")
+	}
+	plain, err := k.aead.Open(nil, sealed[:size], sealed[size:], []byte(name))
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// deriveAEAD 使用 argon2id 从主密钥派生 AES-256-GCM 密钥
+func deriveAEAD(masterKey []byte, p kdfParams) (cipher.AEAD, error) {
+	key := argon2.IDKey(masterKey, p.Salt, p.Time, p.Memory, p.Threads, 32)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,14 @@
+//go:build windows
+
+package server
+
+import (
+	"errors"
+	"net"
+	"time"
+)
+
+// spawn Windows 不支持继承监听套接字，无法平滑重启
+func spawn(listeners []net.Listener, timeout time.Duration) (int, error) {
+	return 0, errors.New("当前平台不支持平滑重启")
+}
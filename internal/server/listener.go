@@ -0,0 +1,56 @@
+package server
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// 平滑重启时父进程传给子进程的环境变量
+const (
+	envListenFDs = "HARBORARK_LISTEN_FDS"
+	envReadyFD   = "HARBORARK_READY_FD"
+)
+
+// listenFDStart 继承的第一个文件描述符，0-2 为标准输入输出
+const listenFDStart = 3
+
+var (
+	inheritOnce sync.Once
+	inherited   int
+	readyFD     int
+)
+
+// loadInherited 读取并清除继承相关的环境变量，避免再次平滑重启时传给孙进程
+func loadInherited() {
+	inheritOnce.Do(func() {
+		inherited, _ = strconv.Atoi(os.Getenv(envListenFDs))
+		readyFD, _ = strconv.Atoi(os.Getenv(envReadyFD))
+		os.Unsetenv(envListenFDs)
+		os.Unsetenv(envReadyFD)
+	})
+}
+
+// listen 优先使用从父进程继承的第 index 个监听套接字，否则新建监听
+func listen(index int, addr string) (net.Listener, error) {
+	loadInherited()
+	if index < inherited {
+		f := os.NewFile(uintptr(listenFDStart+index), "listener-"+strconv.Itoa(index))
+		defer f.Close()
+		return net.FileListener(f)
+	}
+	return net.Listen("tcp", addr)
+}
+
+// notifyReady 通知父进程本进程已开始接收连接
+func notifyReady() error {
+	loadInherited()
+	if readyFD == 0 {
+		return nil
+	}
+	f := os.NewFile(uintptr(readyFD), "ready")
+	defer f.Close()
+	_, err := f.Write([]byte{1})
+	return err
+}
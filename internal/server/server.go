@@ -0,0 +1,163 @@
+package server
+
+import (
+	"HarborArk/internal/logging"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// endpoint 一个监听地址及其 http.Server
+type endpoint struct {
+	srv *http.Server
+	ln  net.Listener
+}
+
+// Server 托管一个或多个 http.Server：收到 SIGINT/SIGTERM 时停止接收新连接并等待进行中的请求完成，
+// 收到 SIGHUP 时将监听套接字交给新启动的进程后再优雅退出，实现不中断连接的重启
+type Server struct {
+	shutdownTimeout time.Duration
+	endpoints       []*endpoint
+	handoffHooks    []func(context.Context) error
+	hooks           []func(context.Context) error
+}
+
+// New 创建 Server，shutdownTimeout 为等待进行中请求完成的最长时间
+func New(shutdownTimeout time.Duration) *Server {
+	return &Server{shutdownTimeout: shutdownTimeout}
+}
+
+// Handle 在 addr 上监听并由 srv 处理请求；平滑重启后的新进程按添加顺序复用继承的套接字。
+// srv.TLSConfig 不为空时以 TLS 方式提供服务
+func (s *Server) Handle(addr string, srv *http.Server) error {
+	ln, err := listen(len(s.endpoints), addr)
+	if err != nil {
+		return fmt.Errorf("监听 %s 失败: %v", addr, err)
+	}
+	s.endpoints = append(s.endpoints, &endpoint{srv: srv, ln: ln})
+	return nil
+}
+
+// OnShutdown 注册关闭钩子，在 HTTP 连接排空后按注册顺序执行，用于停止后台任务、刷新日志等
+func (s *Server) OnShutdown(fn func(context.Context) error) {
+	s.hooks = append(s.hooks, fn)
+}
+
+// OnHandoff 注册平滑重启钩子，在新进程就绪后、排空连接前执行，用于尽早停止后台任务，
+// 避免新旧进程同时处理同一批任务
+func (s *Server) OnHandoff(fn func(context.Context) error) {
+	s.handoffHooks = append(s.handoffHooks, fn)
+}
+
+// Run 开始提供服务并阻塞直到收到退出信号且关闭流程完成
+func (s *Server) Run() error {
+	errCh := make(chan error, len(s.endpoints))
+	for _, ep := range s.endpoints {
+		go func(ep *endpoint) {
+			var err error
+			if ep.srv.TLSConfig != nil {
+				err = ep.srv.ServeTLS(ep.ln, "", "")
+			} else {
+				err = ep.srv.Serve(ep.ln)
+			}
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errCh <- err
+			}
+		}(ep)
+	}
+
+	// 通知发起平滑重启的旧进程：新进程已开始接收连接
+	if err := notifyReady(); err != nil {
+		logging.Named("server").Warn("通知父进程就绪失败", zap.Error(err))
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(sigCh)
+
+	var serveErr error
+	for {
+		select {
+		case serveErr = <-errCh:
+			logging.Named("server").Error("HTTP 服务异常退出", zap.Error(serveErr))
+		case sig := <-sigCh:
+			if sig == syscall.SIGHUP {
+				if err := s.handoff(); err != nil {
+					logging.Named("server").Error("平滑重启失败，继续使用当前进程提供服务", zap.Error(err))
+					continue
+				}
+			}
+			logging.Named("server").Info("收到退出信号，开始优雅关闭", zap.String("signal", sig.String()))
+		}
+		break
+	}
+
+	return errors.Join(serveErr, s.shutdown())
+}
+
+// handoff 启动新进程并把监听套接字交给它，等待新进程就绪
+func (s *Server) handoff() error {
+	listeners := make([]net.Listener, len(s.endpoints))
+	for i, ep := range s.endpoints {
+		listeners[i] = ep.ln
+	}
+	logging.Named("server").Info("收到 SIGHUP，正在启动新进程接管监听套接字")
+	pid, err := spawn(listeners, s.shutdownTimeout)
+	if err != nil {
+		return err
+	}
+	logging.Named("server").Info("新进程已就绪", zap.Int("pid", pid))
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+	for _, hook := range s.handoffHooks {
+		if err := hook(ctx); err != nil {
+			logging.Named("server").Warn("执行平滑重启钩子失败", zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// shutdown 排空 HTTP 连接后执行关闭钩子，超时则强制断开剩余连接
+func (s *Server) shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	var (
+		mu   sync.Mutex
+		errs []error
+		wg   sync.WaitGroup
+	)
+	for _, ep := range s.endpoints {
+		wg.Add(1)
+		go func(ep *endpoint) {
+			defer wg.Done()
+			if err := ep.srv.Shutdown(ctx); err != nil {
+				ep.srv.Close()
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("等待连接关闭超时，已强制断开: %v", err))
+				mu.Unlock()
+			}
+		}(ep)
+	}
+	wg.Wait()
+
+	// 关闭钩子使用独立的超时，避免连接排空耗尽时间后后台任务来不及保存状态
+	hookCtx, hookCancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer hookCancel()
+	for _, hook := range s.hooks {
+		if err := hook(hookCtx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
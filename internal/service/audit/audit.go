@@ -0,0 +1,329 @@
+package audit
+
+import (
+	"HarborArk/internal/i18n"
+	"HarborArk/internal/logging"
+	"HarborArk/internal/tracing"
+	"HarborArk/internal/utils"
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// 审计动作
+const (
+	ActionLogin            = "auth.login"
+	ActionLoginFailed      = "auth.login_failed"
+	ActionLoginLocked      = "auth.locked"
+	ActionUnlock           = "auth.unlock"
+	ActionPermissionChange = "permission.change"
+	ActionShareCreate      = "share.create"
+	ActionFileDelete       = "file.delete"
+	ActionUserCreate       = "user.create"
+	ActionUserDelete       = "user.delete"
+	ActionConfigChange     = "config.change"
+	ActionWebhookCreate    = "webhook.create"
+	ActionWebhookDelete    = "webhook.delete"
+)
+
+// 审计结果
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// Entry 审计日志条目，Hash 覆盖除自身外的全部字段与上一条的 Hash
+type Entry struct {
+	Seq       int64             `json:"seq" example:"1"`
+	Time      time.Time         `json:"time"`
+	Actor     string            `json:"actor" example:"admin"`
+	IP        string            `json:"ip" example:"192.168.1.10"`
+	Action    string            `json:"action" example:"user.create"`
+	Resource  string            `json:"resource" example:"users/3"`
+	Result    string            `json:"result" example:"success"`
+	Detail    map[string]string `json:"detail,omitempty"`
+	RequestID string            `json:"request_id,omitempty" example:"4f3c2a1b9d8e7f60a1b2c3d4e5f60718"`
+	PrevHash  string            `json:"prev_hash"`
+	Hash      string            `json:"hash"`
+}
+
+// digest 计算条目哈希
+func (e Entry) digest() string {
+	e.Hash = ""
+	content, _ := json.Marshal(e)
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+var std *Logger
+
+// Logger 追加写入的哈希链审计日志，与应用日志相互独立
+type Logger struct {
+	mu       sync.Mutex
+	path     string
+	file     *os.File
+	size     int64
+	seq      int64
+	lastHash string
+}
+
+// Init 初始化全局审计日志
+func Init(path string) error {
+	l, err := Open(path)
+	if err != nil {
+		return err
+	}
+	std = l
+	return nil
+}
+
+// Default 获取全局审计日志，未启用时返回 nil
+func Default() *Logger {
+	return std
+}
+
+// Record 写入全局审计日志，未启用时忽略，详情中的敏感字段按日志脱敏规则隐藏
+func Record(ctx context.Context, e Entry) {
+	if std == nil {
+		return
+	}
+	e.Detail = logging.RedactMap(e.Detail)
+	if err := std.Record(ctx, e); err != nil {
+		logging.Named("audit").Error("写入审计日志失败", zap.String("action", e.Action), zap.Error(err))
+	}
+}
+
+// Open 打开审计日志文件，并从最后一条记录恢复哈希链
+func Open(path string) (*Logger, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, i18n.NewError("errors.audit.mkdir_failed", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, i18n.NewError("errors.audit.open_failed", err)
+	}
+
+	l := &Logger{path: path, file: file}
+	if err := l.resume(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// resume 从文件最后一条记录恢复序号与哈希链位置
+func (l *Logger) resume() error {
+	l.seq, l.lastHash = 0, ""
+	if err := l.scan(-1, func(e Entry) bool {
+		l.seq = e.Seq
+		l.lastHash = e.Hash
+		return true
+	}); err != nil {
+		return err
+	}
+	info, err := l.file.Stat()
+	if err != nil {
+		return err
+	}
+	l.size = info.Size()
+	return nil
+}
+
+// Close 关闭审计日志
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+// Record 追加一条审计记录
+func (l *Logger) Record(ctx context.Context, e Entry) (err error) {
+	_, span := tracing.Start(ctx, "audit.record", trace.WithAttributes(attribute.String("audit.action", e.Action)))
+	defer func() { tracing.End(span, err) }()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// 平滑重启期间新旧进程会短暂同时写入，加文件锁并在文件被其他进程追加后重新定位链尾
+	if err := utils.LockFile(l.file); err != nil {
+		return err
+	}
+	defer utils.UnlockFile(l.file)
+	if info, err := l.file.Stat(); err != nil {
+		return err
+	} else if info.Size() != l.size {
+		if err := l.resume(); err != nil {
+			return err
+		}
+	}
+
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	if e.RequestID == "" {
+		e.RequestID = logging.RequestID(ctx)
+	}
+	if e.Result == "" {
+		e.Result = ResultSuccess
+	}
+	e.Seq = l.seq + 1
+	e.PrevHash = l.lastHash
+	e.Hash = e.digest()
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	if err := l.file.Sync(); err != nil {
+		return err
+	}
+
+	l.size += int64(len(line) + 1)
+	l.seq = e.Seq
+	l.lastHash = e.Hash
+	return nil
+}
+
+// snapshot 在文件锁内取得当前文件长度。长度总是落在完整的记录之后，
+// 查询与校验只读取这一长度之内的内容，不会读到其他进程写了一半的记录
+func (l *Logger) snapshot() (int64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := utils.LockFile(l.file); err != nil {
+		return 0, err
+	}
+	defer utils.UnlockFile(l.file)
+	info, err := l.file.Stat()
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// Query 按条件查询审计记录，按时间倒序返回，同时返回匹配总数。
+// Limit 大于 0 时只在内存中保留最新的 Offset+Limit 条匹配记录
+func (l *Logger) Query(ctx context.Context, f Filter) ([]Entry, int, error) {
+	_, span := tracing.Start(ctx, "audit.query")
+	defer span.End()
+
+	size, err := l.snapshot()
+	if err != nil {
+		return nil, 0, err
+	}
+	keep := f.Offset + f.Limit
+	var matched []Entry
+	total := 0
+	err = l.scan(size, func(e Entry) bool {
+		if f.Match(e) {
+			total++
+			matched = append(matched, e)
+			if f.Limit > 0 && len(matched) > keep {
+				matched = matched[1:]
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for i, j := 0, len(matched)-1; i < j; i, j = i+1, j-1 {
+		matched[i], matched[j] = matched[j], matched[i]
+	}
+	if f.Offset > 0 {
+		if f.Offset >= len(matched) {
+			return []Entry{}, total, nil
+		}
+		matched = matched[f.Offset:]
+	}
+	if f.Limit > 0 && len(matched) > f.Limit {
+		matched = matched[:f.Limit]
+	}
+	if matched == nil {
+		matched = []Entry{}
+	}
+	return matched, total, nil
+}
+
+// Verify 校验整条哈希链，返回通过校验的条目数，发现篡改时返回错误
+func (l *Logger) Verify() (int64, error) {
+	size, err := l.snapshot()
+	if err != nil {
+		return 0, err
+	}
+	var (
+		count    int64
+		prevHash string
+		verr     error
+	)
+	err = l.scan(size, func(e Entry) bool {
+		switch {
+		case e.Seq != count+1:
+			verr = i18n.NewError("api.audit.bad_seq", count+1, e.Seq)
+		case e.PrevHash != prevHash:
+			verr = i18n.NewError("api.audit.bad_prev_hash", e.Seq)
+		case e.digest() != e.Hash:
+			verr = i18n.NewError("api.audit.bad_hash", e.Seq)
+		}
+		if verr != nil {
+			return false
+		}
+		count++
+		prevHash = e.Hash
+		return true
+	})
+	if err != nil {
+		return count, err
+	}
+	return count, verr
+}
+
+// scan 顺序读取文件前 size 字节中的记录，size 小于 0 时读取整个文件，fn 返回 false 时停止
+func (l *Logger) scan(size int64, fn func(Entry) bool) error {
+	file, err := os.Open(l.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return i18n.NewError("api.audit.open_failed", err)
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if size >= 0 {
+		r = io.LimitReader(file, size)
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return i18n.NewError("api.audit.parse_failed", line, err)
+		}
+		if !fn(e) {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,207 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// openTemp 在临时目录中打开审计日志并写入 n 条记录
+func openTemp(t *testing.T, n int) (*Logger, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "audit.log")
+	l, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	for i := 1; i <= n; i++ {
+		actor := "alice"
+		if i%2 == 0 {
+			actor = "bob"
+		}
+		if err := l.Record(context.Background(), Entry{Actor: actor, Action: ActionLogin, Resource: fmt.Sprintf("users/%d", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return l, path
+}
+
+// editLines 读取审计日志的各行，经 fn 修改后写回
+func editLines(t *testing.T, path string, fn func([]string) []string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	lines = fn(lines)
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// editEntry 修改一行记录，rehash 为 true 时同时重新计算该条的哈希
+func editEntry(t *testing.T, line string, rehash bool, fn func(*Entry)) string {
+	t.Helper()
+	var e Entry
+	if err := json.Unmarshal([]byte(line), &e); err != nil {
+		t.Fatal(err)
+	}
+	fn(&e)
+	if rehash {
+		e.Hash = e.digest()
+	}
+	data, _ := json.Marshal(e)
+	return string(data)
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name    string
+		tamper  func(t *testing.T, lines []string) []string
+		want    int64
+		wantErr bool
+	}{
+		{
+			name:   "intact",
+			tamper: func(t *testing.T, lines []string) []string { return lines },
+			want:   5,
+		},
+		{
+			name: "modified field",
+			tamper: func(t *testing.T, lines []string) []string {
+				lines[2] = editEntry(t, lines[2], false, func(e *Entry) { e.Actor = "mallory" })
+				return lines
+			},
+			want:    2,
+			wantErr: true,
+		},
+		{
+			name: "modified and rehashed",
+			tamper: func(t *testing.T, lines []string) []string {
+				lines[2] = editEntry(t, lines[2], true, func(e *Entry) { e.Result = ResultFailure })
+				return lines
+			},
+			want:    3,
+			wantErr: true,
+		},
+		{
+			name: "deleted entry",
+			tamper: func(t *testing.T, lines []string) []string {
+				return append(lines[:2], lines[3:]...)
+			},
+			want:    2,
+			wantErr: true,
+		},
+		{
+			name: "reordered entries",
+			tamper: func(t *testing.T, lines []string) []string {
+				lines[1], lines[2] = lines[2], lines[1]
+				return lines
+			},
+			want:    1,
+			wantErr: true,
+		},
+		{
+			name: "forged chain head",
+			tamper: func(t *testing.T, lines []string) []string {
+				lines[0] = editEntry(t, lines[0], true, func(e *Entry) { e.PrevHash = "00" })
+				return lines
+			},
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, path := openTemp(t, 5)
+			editLines(t, path, func(lines []string) []string { return tt.tamper(t, lines) })
+
+			got, err := l.Verify()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Verify() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResume(t *testing.T) {
+	l, path := openTemp(t, 3)
+	l.Close()
+
+	// 重新打开后从最后一条记录继续哈希链
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.Close()
+	if reopened.seq != 3 {
+		t.Fatalf("resume seq = %d, want 3", reopened.seq)
+	}
+
+	// 模拟平滑重启期间新旧进程交替写入同一文件
+	other, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+	for i, w := range []*Logger{reopened, other, reopened, other} {
+		if err := w.Record(context.Background(), Entry{Actor: "alice", Action: ActionConfigChange, Resource: fmt.Sprint(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	count, err := reopened.Verify()
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if count != 7 {
+		t.Errorf("Verify() = %d, want 7", count)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	l, _ := openTemp(t, 10)
+
+	tests := []struct {
+		name      string
+		filter    Filter
+		wantTotal int
+		want      []string
+	}{
+		{"first page", Filter{Limit: 3}, 10, []string{"users/10", "users/9", "users/8"}},
+		{"second page", Filter{Limit: 3, Offset: 3}, 10, []string{"users/7", "users/6", "users/5"}},
+		{"last page", Filter{Limit: 3, Offset: 9}, 10, []string{"users/1"}},
+		{"beyond end", Filter{Limit: 3, Offset: 10}, 10, []string{}},
+		{"filtered", Filter{Actor: "bob", Limit: 2}, 5, []string{"users/10", "users/8"}},
+		{"filtered offset", Filter{Actor: "alice", Limit: 2, Offset: 4}, 5, []string{"users/1"}},
+		{"resource prefix", Filter{Resource: "users/1"}, 2, []string{"users/10", "users/1"}},
+		{"no match", Filter{Action: "share."}, 0, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, total, err := l.Query(context.Background(), tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("total = %d, want %d", total, tt.wantTotal)
+			}
+			got := make([]string, 0, len(entries))
+			for _, e := range entries {
+				got = append(got, e.Resource)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Query() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
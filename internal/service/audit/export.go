@@ -0,0 +1,97 @@
+package audit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Filter 审计记录查询条件，空字段表示不过滤
+type Filter struct {
+	Actor    string
+	Action   string
+	Resource string
+	Result   string
+	Since    time.Time
+	Until    time.Time
+	Limit    int
+	Offset   int
+}
+
+// Match 判断条目是否满足条件；Action 与 Resource 支持前缀匹配，如 "auth." 或 "users/"
+func (f Filter) Match(e Entry) bool {
+	switch {
+	case f.Actor != "" && e.Actor != f.Actor:
+		return false
+	case f.Action != "" && !strings.HasPrefix(e.Action, f.Action):
+		return false
+	case f.Resource != "" && !strings.HasPrefix(e.Resource, f.Resource):
+		return false
+	case f.Result != "" && e.Result != f.Result:
+		return false
+	case !f.Since.IsZero() && e.Time.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && e.Time.After(f.Until):
+		return false
+	}
+	return true
+}
+
+// csvHeader CSV 导出列
+var csvHeader = []string{"seq", "time", "actor", "ip", "action", "resource", "result", "detail", "request_id", "prev_hash", "hash"}
+
+// WriteCSV 以 CSV 格式导出审计记录
+func WriteCSV(w io.Writer, entries []Entry) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		record := []string{
+			strconv.FormatInt(e.Seq, 10),
+			e.Time.Format(time.RFC3339Nano),
+			e.Actor,
+			e.IP,
+			e.Action,
+			e.Resource,
+			e.Result,
+			formatDetail(e.Detail),
+			e.RequestID,
+			e.PrevHash,
+			e.Hash,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON 以 JSON 数组格式导出审计记录
+func WriteJSON(w io.Writer, entries []Entry) error {
+	if entries == nil {
+		entries = []Entry{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+// formatDetail 将详情格式化为按键排序的 k=v 列表
+func formatDetail(detail map[string]string) string {
+	keys := make([]string, 0, len(detail))
+	for k := range detail {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+detail[k])
+	}
+	return strings.Join(pairs, ";")
+}
@@ -0,0 +1,118 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"time"
+)
+
+// pollInterval 检查日志文件新内容的间隔。轮询而不是监听文件事件，以便在各平台上一致地处理轮转
+const pollInterval = 500 * time.Millisecond
+
+// stopCtx 服务关闭时取消，结束全部实时跟踪
+var stopCtx, stopFollowing = context.WithCancel(context.Background())
+
+// StopFollowing 结束全部实时跟踪。SSE 连接不会自行结束，需在 HTTP 服务关闭前调用
+func StopFollowing() {
+	stopFollowing()
+}
+
+// Follow 从当前末尾开始跟踪日志文件，将满足条件的新日志依次交给 fn，
+// 文件被轮转后自动从新文件开头继续。ctx 结束、服务关闭或 fn 返回错误时返回
+func Follow(ctx context.Context, filename string, f Filter, fn func(Entry) error) error {
+	if err := f.Prepare(); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-stopCtx.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	var (
+		file    *os.File
+		info    os.FileInfo
+		offset  int64
+		pending []byte
+	)
+	defer func() {
+		if file != nil {
+			file.Close()
+		}
+	}()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for first := true; ; first = false {
+		current, err := os.Stat(filename)
+		switch {
+		case err != nil:
+			// 轮转期间文件可能短暂不存在
+		case file == nil || !os.SameFile(info, current) || current.Size() < offset:
+			if file != nil {
+				if os.SameFile(info, current) {
+					// 文件被截断
+					pending = nil
+				} else if data, err := readFrom(file, offset); err == nil {
+					// 读完轮转前写入旧文件的内容
+					pending = append(pending, data...)
+				}
+				file.Close()
+			}
+			if file, err = os.Open(filename); err != nil {
+				file = nil
+				break
+			}
+			info, offset = current, 0
+			if first {
+				// 只跟踪新写入的日志
+				offset = current.Size()
+			}
+		}
+
+		if file != nil {
+			data, err := readFrom(file, offset)
+			if err != nil {
+				return err
+			}
+			offset += int64(len(data))
+			pending = append(pending, data...)
+			for {
+				i := bytes.IndexByte(pending, '\n')
+				if i < 0 {
+					break
+				}
+				line := string(bytes.TrimRight(pending[:i], "\r"))
+				pending = pending[i+1:]
+				if line == "" {
+					continue
+				}
+				if e := ParseLine(line); f.Match(e, line) {
+					if err := fn(e); err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// readFrom 读取 offset 之后新写入的内容
+func readFrom(file *os.File, offset int64) ([]byte, error) {
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(io.LimitReader(file, maxLineSize))
+}
@@ -0,0 +1,259 @@
+package logs
+
+import (
+	"HarborArk/internal/i18n"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// TimeLayout 日志文件中的时间格式，与日志编码器一致，使用本地时区
+const TimeLayout = "2006-01-02 15:04:05"
+
+// maxLineSize 单行日志的最大长度，panic 日志包含调用栈时可能较长
+const maxLineSize = 4 << 20
+
+// Entry 一条日志
+type Entry struct {
+	Time    time.Time              `json:"time"`
+	Level   string                 `json:"level" example:"INFO"`
+	Logger  string                 `json:"logger,omitempty" example:"webhook"`
+	Message string                 `json:"message" example:"/api/v1/users"`
+	Caller  string                 `json:"caller,omitempty" example:"middleware/logger.go:134"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
+	// Raw 无法按 JSON 解析的原始行，如 console 编码的日志
+	Raw string `json:"raw,omitempty"`
+}
+
+// Filter 日志查询条件，空字段表示不过滤
+type Filter struct {
+	Since     time.Time
+	Until     time.Time
+	Level     string
+	Path      string
+	RequestID string
+	Text      string
+	Limit     int
+	Offset    int
+
+	minLevel zapcore.Level
+	text     string
+}
+
+// Prepare 解析并校验查询条件，使用 Match 前调用
+func (f *Filter) Prepare() error {
+	f.minLevel = zapcore.DebugLevel
+	if f.Level != "" {
+		if err := f.minLevel.UnmarshalText([]byte(f.Level)); err != nil {
+			return i18n.NewError("api.logs.invalid_level", f.Level)
+		}
+	}
+	f.text = strings.ToLower(f.Text)
+	return nil
+}
+
+// Match 判断条目是否满足条件：Level 为最低级别，Path 为前缀匹配，Text 在整行中不区分大小写查找
+func (f Filter) Match(e Entry, line string) bool {
+	if f.text != "" && !strings.Contains(strings.ToLower(line), f.text) {
+		return false
+	}
+	if e.Raw != "" {
+		// 非 JSON 行只支持全文检索
+		return f.Since.IsZero() && f.Until.IsZero() && f.Level == "" && f.Path == "" && f.RequestID == ""
+	}
+	var level zapcore.Level
+	if f.Level != "" && (level.UnmarshalText([]byte(e.Level)) != nil || level < f.minLevel) {
+		return false
+	}
+	switch {
+	case !f.Since.IsZero() && e.Time.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && e.Time.After(f.Until):
+		return false
+	case f.Path != "" && !strings.HasPrefix(stringField(e, "path"), f.Path):
+		return false
+	case f.RequestID != "" && stringField(e, "request_id") != f.RequestID:
+		return false
+	}
+	return true
+}
+
+func stringField(e Entry, key string) string {
+	s, _ := e.Fields[key].(string)
+	return s
+}
+
+// ParseTime 解析时间参数，支持 RFC3339、"2006-01-02 15:04:05"（本地时区）以及相对于 now 的时长，如 1h 表示一小时前
+func ParseTime(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(TimeLayout, s, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, i18n.NewError("api.logs.invalid_time", s, TimeLayout)
+}
+
+// ParseLine 解析一行 JSON 日志，无法解析时返回仅包含 Raw 的条目
+func ParseLine(line string) Entry {
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &fields); err != nil {
+		return Entry{Raw: line}
+	}
+	e := Entry{Fields: fields}
+	take := func(key string) string {
+		s, _ := fields[key].(string)
+		delete(fields, key)
+		return s
+	}
+	e.Time, _ = time.ParseInLocation(TimeLayout, take("timestamp"), time.Local)
+	e.Level = take("level")
+	e.Logger = take("logger")
+	e.Message = take("message")
+	e.Caller = take("caller")
+	return e
+}
+
+// LogFile 当前日志文件或轮转后的备份
+type LogFile struct {
+	Path    string
+	ModTime time.Time
+}
+
+// Files 返回 filename 及其轮转备份（含 gzip 压缩的备份），按时间从旧到新排列，当前文件在最后
+func Files(filename string) ([]LogFile, error) {
+	ext := filepath.Ext(filename)
+	prefix := strings.TrimSuffix(filepath.Base(filename), ext) + "-"
+	entries, err := os.ReadDir(filepath.Dir(filename))
+	if err != nil {
+		return nil, i18n.NewError("api.logs.read_dir_failed", err)
+	}
+
+	var files []LogFile
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) ||
+			!(strings.HasSuffix(name, ext) || strings.HasSuffix(name, ext+".gz")) {
+			continue
+		}
+		if info, err := entry.Info(); err == nil {
+			files = append(files, LogFile{Path: filepath.Join(filepath.Dir(filename), name), ModTime: info.ModTime()})
+		}
+	}
+	// 备份文件名中的时间戳格式固定，按名称排序即按时间排序
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+	if info, err := os.Stat(filename); err == nil {
+		files = append(files, LogFile{Path: filename, ModTime: info.ModTime()})
+	}
+	return files, nil
+}
+
+// Search 在当前日志文件及其轮转备份中查询，按时间倒序返回分页结果与匹配总数
+func Search(ctx context.Context, filename string, f Filter) ([]Entry, int, error) {
+	if err := f.Prepare(); err != nil {
+		return nil, 0, err
+	}
+	files, err := Files(filename)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 只保留最新的 Offset+Limit 条
+	keep := f.Offset + f.Limit
+	var matched []Entry
+	total := 0
+	for _, file := range files {
+		if !f.Since.IsZero() && file.ModTime.Before(f.Since) {
+			continue
+		}
+		done, err := scanFile(ctx, file.Path, func(e Entry, line string) bool {
+			if !f.Until.IsZero() && e.Raw == "" && e.Time.After(f.Until) {
+				return false
+			}
+			if f.Match(e, line) {
+				total++
+				matched = append(matched, e)
+				if f.Limit > 0 && len(matched) > keep {
+					matched = matched[1:]
+				}
+			}
+			return true
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+		if done {
+			break
+		}
+	}
+
+	for i, j := 0, len(matched)-1; i < j; i, j = i+1, j-1 {
+		matched[i], matched[j] = matched[j], matched[i]
+	}
+	if f.Offset > 0 {
+		if f.Offset >= len(matched) {
+			return []Entry{}, total, nil
+		}
+		matched = matched[f.Offset:]
+	}
+	if f.Limit > 0 && len(matched) > f.Limit {
+		matched = matched[:f.Limit]
+	}
+	if matched == nil {
+		matched = []Entry{}
+	}
+	return matched, total, nil
+}
+
+// scanFile 逐行读取日志文件，fn 返回 false 时停止并返回 done=true
+func scanFile(ctx context.Context, path string, fn func(Entry, string) bool) (done bool, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// 查询期间被轮转清理
+			return false, nil
+		}
+		return false, i18n.NewError("api.logs.open_failed", err)
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return false, i18n.NewError("api.logs.decompress_failed", path, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), maxLineSize)
+	for n := 0; scanner.Scan(); n++ {
+		if n%1000 == 0 && ctx.Err() != nil {
+			return true, ctx.Err()
+		}
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		if !fn(ParseLine(line), line) {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
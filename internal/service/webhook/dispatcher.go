@@ -0,0 +1,396 @@
+package webhook
+
+import (
+	"HarborArk/config"
+	"HarborArk/internal/i18n"
+	"HarborArk/internal/logging"
+	"HarborArk/internal/metrics"
+	"HarborArk/internal/tracing"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// pollInterval 发件箱轮询间隔
+const pollInterval = time.Second
+
+var std *Dispatcher
+
+// Dispatcher 从持久化发件箱中取出事件并投递到 Hook
+type Dispatcher struct {
+	cfg    config.WebhookConfig
+	store  *Store
+	client *http.Client
+
+	mu       sync.Mutex
+	inflight map[string]bool
+
+	queue    chan Delivery
+	wake     chan struct{}
+	stop     chan struct{}
+	wg       sync.WaitGroup
+	lastBeat atomic.Int64
+	stopped  atomic.Bool
+	stopOnce sync.Once
+}
+
+// Init 初始化全局 Webhook 投递器并启动后台投递
+func Init(cfg config.WebhookConfig) error {
+	if !cfg.Enabled {
+		return nil
+	}
+	store, err := OpenStore(cfg.StoreFile, cfg.HistoryLimit)
+	if err != nil {
+		return err
+	}
+	std = NewDispatcher(cfg, store)
+	std.Start()
+	metrics.RegisterQueue("webhook", std.QueueDepth)
+	return nil
+}
+
+// Default 获取全局投递器，未启用时返回 nil
+func Default() *Dispatcher {
+	return std
+}
+
+// Publish 向全局投递器发布事件，未启用时忽略
+func Publish(ctx context.Context, event string, data interface{}) {
+	if std == nil {
+		return
+	}
+	if err := std.Publish(ctx, event, data); err != nil {
+		logging.Named("webhook").Error("发布 webhook 事件失败", zap.String("event", event), zap.Error(err))
+	}
+}
+
+// NewDispatcher 创建投递器
+func NewDispatcher(cfg config.WebhookConfig, store *Store) *Dispatcher {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 1
+	}
+	return &Dispatcher{
+		cfg:      cfg,
+		store:    store,
+		client:   &http.Client{Timeout: cfg.Timeout, Transport: otelhttp.NewTransport(http.DefaultTransport)},
+		inflight: make(map[string]bool),
+		queue:    make(chan Delivery),
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+	}
+}
+
+// Store 获取底层存储
+func (d *Dispatcher) Store() *Store {
+	return d.store
+}
+
+// Start 启动轮询协程与投递协程
+func (d *Dispatcher) Start() {
+	d.lastBeat.Store(time.Now().UnixNano())
+	d.wg.Add(1)
+	go d.poll()
+	for i := 0; i < d.cfg.Workers; i++ {
+		d.wg.Add(1)
+		go d.work()
+	}
+}
+
+// Stop 停止投递并等待进行中的投递完成，未完成的记录保留在发件箱中待下次启动继续。可重复调用
+func (d *Dispatcher) Stop(ctx context.Context) error {
+	d.stopOnce.Do(func() {
+		d.stopped.Store(true)
+		close(d.stop)
+	})
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Publish 为每个订阅了该事件的 Hook 写入一条待投递记录，并保存追踪上下文与请求 ID 供投递时关联
+func (d *Dispatcher) Publish(ctx context.Context, event string, data interface{}) error {
+	var deliveries []*Delivery
+	for _, h := range d.store.Hooks() {
+		if !h.Matches(event) {
+			continue
+		}
+		delivery, err := newDelivery(h.ID, event, data)
+		if err != nil {
+			return err
+		}
+		delivery.TraceContext = tracing.Inject(ctx)
+		delivery.RequestID = logging.RequestID(ctx)
+		deliveries = append(deliveries, delivery)
+	}
+	if err := d.store.AddDeliveries(deliveries...); err != nil {
+		return err
+	}
+	if len(deliveries) > 0 {
+		d.notify()
+	}
+	return nil
+}
+
+// Ping 向指定 Hook 发送测试事件，不受事件过滤影响
+func (d *Dispatcher) Ping(hookID string) error {
+	hook, err := d.store.Hook(hookID)
+	if err != nil {
+		return err
+	}
+	delivery, err := newDelivery(hook.ID, EventWebhookPing, map[string]interface{}{
+		"hook_id": hook.ID,
+		"events":  hook.Events,
+	})
+	if err != nil {
+		return err
+	}
+	if err := d.store.AddDeliveries(delivery); err != nil {
+		return err
+	}
+	d.notify()
+	return nil
+}
+
+// newDelivery 构造事件负载与待投递记录
+func newDelivery(hookID, event string, data interface{}) (*Delivery, error) {
+	id := newID()
+	now := time.Now()
+	payload, err := json.Marshal(map[string]interface{}{
+		"id":        id,
+		"event":     event,
+		"timestamp": now.UTC().Format(time.RFC3339),
+		"data":      data,
+	})
+	if err != nil {
+		return nil, i18n.NewError("errors.webhook.marshal_failed", err)
+	}
+	return &Delivery{
+		ID:          id,
+		HookID:      hookID,
+		Event:       event,
+		Payload:     payload,
+		Status:      StatusPending,
+		NextAttempt: now,
+		CreatedAt:   now,
+	}, nil
+}
+
+// Redeliver 以相同负载重新投递一条历史记录
+func (d *Dispatcher) Redeliver(hookID, deliveryID string) (Delivery, error) {
+	if _, err := d.store.Hook(hookID); err != nil {
+		return Delivery{}, err
+	}
+	orig, err := d.store.Delivery(hookID, deliveryID)
+	if err != nil {
+		return Delivery{}, err
+	}
+	now := time.Now()
+	redelivery := &Delivery{
+		ID:           newID(),
+		HookID:       hookID,
+		Event:        orig.Event,
+		Payload:      orig.Payload,
+		Status:       StatusPending,
+		NextAttempt:  now,
+		RedeliveryOf: orig.ID,
+		CreatedAt:    now,
+	}
+	if err := d.store.AddDeliveries(redelivery); err != nil {
+		return Delivery{}, err
+	}
+	d.notify()
+	return *redelivery, nil
+}
+
+// Alive 检查投递协程是否仍在运行。轮询协程在等待投递协程时可能阻塞至一次投递超时，
+// 因此心跳超过请求超时加若干轮询周期仍未更新才视为卡死
+func (d *Dispatcher) Alive(ctx context.Context) error {
+	if d.stopped.Load() {
+		return i18n.NewError("api.health.webhook_stopped")
+	}
+	since := time.Since(time.Unix(0, d.lastBeat.Load()))
+	if since > d.cfg.Timeout+5*pollInterval {
+		return i18n.NewError("api.health.webhook_stalled", since.Truncate(time.Second).String())
+	}
+	return nil
+}
+
+// QueueDepth 获取发件箱中待投递记录数量
+func (d *Dispatcher) QueueDepth() int {
+	return d.store.Pending()
+}
+
+func (d *Dispatcher) notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// poll 轮询发件箱，将到期记录分发给投递协程
+func (d *Dispatcher) poll() {
+	defer d.wg.Done()
+	defer close(d.queue)
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.lastBeat.Store(time.Now().UnixNano())
+		d.mu.Lock()
+		due := d.store.Due(time.Now(), d.cfg.Workers, d.inflight)
+		for _, item := range due {
+			d.inflight[item.ID] = true
+		}
+		d.mu.Unlock()
+
+		for _, item := range due {
+			select {
+			case d.queue <- item:
+			case <-d.stop:
+				return
+			}
+		}
+
+		select {
+		case <-d.stop:
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// work 投递协程
+func (d *Dispatcher) work() {
+	defer d.wg.Done()
+	for item := range d.queue {
+		d.deliver(item)
+		d.mu.Lock()
+		delete(d.inflight, item.ID)
+		d.mu.Unlock()
+	}
+}
+
+// deliver 执行一次投递并按指数退避安排重试
+func (d *Dispatcher) deliver(item Delivery) {
+	hook, err := d.store.Hook(item.HookID)
+	if err != nil {
+		return
+	}
+
+	ctx, span := tracing.Start(tracing.Extract(context.Background(), item.TraceContext), "webhook.deliver",
+		trace.WithAttributes(
+			attribute.String("webhook.hook_id", hook.ID),
+			attribute.String("webhook.delivery_id", item.ID),
+			attribute.String("webhook.event", item.Event),
+			attribute.Int("webhook.attempt", item.Attempts+1),
+		),
+	)
+
+	item.Attempts++
+	status, err := d.send(ctx, hook, item)
+	tracing.End(span, err)
+	item.ResponseStatus = status
+	now := time.Now()
+
+	switch {
+	case err == nil:
+		item.Status = StatusSuccess
+		item.LastError = ""
+		item.CompletedAt = &now
+	case item.Attempts >= d.cfg.MaxAttempts:
+		item.Status = StatusFailed
+		item.LastError = err.Error()
+		item.CompletedAt = &now
+	default:
+		item.LastError = err.Error()
+		item.NextAttempt = now.Add(d.backoff(item.Attempts))
+	}
+
+	if err != nil {
+		logging.Named("webhook").Warn("webhook 投递失败",
+			zap.String("hook", hook.ID),
+			zap.String("delivery", item.ID),
+			zap.String("event", item.Event),
+			zap.Int("attempts", item.Attempts),
+			zap.String("status", item.Status),
+			zap.String("trace_id", tracing.TraceID(ctx)),
+			zap.String("request_id", item.RequestID),
+			zap.Error(err),
+		)
+	}
+
+	if err := d.store.UpdateDelivery(item); err != nil && err != ErrDeliveryNotFound {
+		logging.Named("webhook").Error("更新 webhook 投递记录失败", zap.String("delivery", item.ID), zap.Error(err))
+	}
+}
+
+// send 发送签名后的请求，返回响应状态码
+func (d *Dispatcher) send(ctx context.Context, hook Hook, item Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(item.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "HarborArk-Webhook")
+	req.Header.Set(HeaderEvent, item.Event)
+	req.Header.Set(HeaderDelivery, item.ID)
+	// 每次尝试使用新的时间戳，重试与重新投递不会因超出接收方的容忍窗口而失败
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(hook.Secret, timestamp, item.Payload))
+	if item.RequestID != "" {
+		// 接收方可据此关联触发事件的 API 请求
+		req.Header.Set(HeaderRequestID, item.RequestID)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, i18n.NewError("errors.webhook.bad_status", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff 计算第 attempts 次失败后的重试间隔
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	wait := d.cfg.InitialBackoff
+	if wait <= 0 {
+		wait = time.Second
+	}
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if d.cfg.MaxBackoff > 0 && wait >= d.cfg.MaxBackoff {
+			return d.cfg.MaxBackoff
+		}
+	}
+	return wait
+}
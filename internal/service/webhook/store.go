@@ -0,0 +1,298 @@
+package webhook
+
+import (
+	"HarborArk/internal/i18n"
+	"HarborArk/internal/logging"
+	"HarborArk/internal/utils"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrHookNotFound     = i18n.NewError("errors.webhook.hook_not_found")
+	ErrDeliveryNotFound = i18n.NewError("errors.webhook.delivery_not_found")
+)
+
+// storeData 持久化文件结构
+type storeData struct {
+	Hooks      []*Hook     `json:"hooks"`
+	Deliveries []*Delivery `json:"deliveries"`
+}
+
+// Store 基于 JSON 文件的 Hook 与发件箱存储。
+// 平滑重启期间新旧进程会短暂共用同一文件，写入时加文件锁，并在文件被其他进程修改后重新加载
+type Store struct {
+	mu           sync.Mutex
+	path         string
+	historyLimit int
+	lock         *os.File
+	modTime      time.Time
+	size         int64
+	data         storeData
+}
+
+// OpenStore 打开存储文件，不存在时创建空存储
+func OpenStore(path string, historyLimit int) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, i18n.NewError("errors.webhook.mkdir_failed", err)
+	}
+	lock, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, i18n.NewError("errors.webhook.lock_failed", err)
+	}
+
+	s := &Store{path: path, historyLimit: historyLimit, lock: lock}
+	if err := s.reload(); err != nil {
+		lock.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// reload 文件自上次读写后发生变化时重新加载，调用方需持有锁
+func (s *Store) reload() error {
+	info, err := os.Stat(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return i18n.NewError("errors.webhook.read_failed", err)
+	}
+	if info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return nil
+	}
+
+	content, err := os.ReadFile(s.path)
+	if err != nil {
+		return i18n.NewError("errors.webhook.read_failed", err)
+	}
+	var data storeData
+	if len(content) > 0 {
+		if err := json.Unmarshal(content, &data); err != nil {
+			return i18n.NewError("errors.webhook.parse_failed", err)
+		}
+	}
+	s.data = data
+	s.modTime, s.size = info.ModTime(), info.Size()
+	return nil
+}
+
+// refresh 读取前同步其他进程的修改，失败时沿用内存中的数据
+func (s *Store) refresh() {
+	if err := s.reload(); err != nil {
+		logging.Named("webhook").Warn("重新加载 webhook 存储失败", zap.Error(err))
+	}
+}
+
+// update 在文件锁保护下加载最新数据、执行修改并写回
+func (s *Store) update(fn func() error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := utils.LockFile(s.lock); err != nil {
+		return err
+	}
+	defer utils.UnlockFile(s.lock)
+
+	if err := s.reload(); err != nil {
+		return err
+	}
+	if err := fn(); err != nil {
+		return err
+	}
+	return s.save()
+}
+
+// save 原子写入存储文件，调用方需持有锁
+func (s *Store) save() error {
+	content, err := json.MarshalIndent(&s.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0600); err != nil {
+		return i18n.NewError("errors.webhook.write_failed", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return err
+	}
+	if info, err := os.Stat(s.path); err == nil {
+		s.modTime, s.size = info.ModTime(), info.Size()
+	}
+	return nil
+}
+
+// AddHook 新增 Hook
+func (s *Store) AddHook(h *Hook) error {
+	return s.update(func() error {
+		s.data.Hooks = append(s.data.Hooks, h)
+		return nil
+	})
+}
+
+// DeleteHook 删除 Hook 及其投递记录
+func (s *Store) DeleteHook(id string) error {
+	return s.update(func() error {
+		idx := -1
+		for i, h := range s.data.Hooks {
+			if h.ID == id {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return ErrHookNotFound
+		}
+		s.data.Hooks = append(s.data.Hooks[:idx], s.data.Hooks[idx+1:]...)
+
+		kept := s.data.Deliveries[:0]
+		for _, d := range s.data.Deliveries {
+			if d.HookID != id {
+				kept = append(kept, d)
+			}
+		}
+		s.data.Deliveries = kept
+		return nil
+	})
+}
+
+// Hook 获取指定 Hook 的副本
+func (s *Store) Hook(id string) (Hook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refresh()
+
+	for _, h := range s.data.Hooks {
+		if h.ID == id {
+			return *h, nil
+		}
+	}
+	return Hook{}, ErrHookNotFound
+}
+
+// Hooks 获取全部 Hook 的副本
+func (s *Store) Hooks() []Hook {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refresh()
+
+	hooks := make([]Hook, 0, len(s.data.Hooks))
+	for _, h := range s.data.Hooks {
+		hooks = append(hooks, *h)
+	}
+	return hooks
+}
+
+// AddDeliveries 批量写入待投递记录
+func (s *Store) AddDeliveries(deliveries ...*Delivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return s.update(func() error {
+		s.data.Deliveries = append(s.data.Deliveries, deliveries...)
+		return nil
+	})
+}
+
+// UpdateDelivery 更新投递结果并裁剪历史记录
+func (s *Store) UpdateDelivery(d Delivery) error {
+	return s.update(func() error {
+		for i, existing := range s.data.Deliveries {
+			if existing.ID == d.ID {
+				s.data.Deliveries[i] = &d
+				s.prune(d.HookID)
+				return nil
+			}
+		}
+		return ErrDeliveryNotFound
+	})
+}
+
+// Delivery 获取指定投递记录的副本
+func (s *Store) Delivery(hookID, id string) (Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refresh()
+
+	for _, d := range s.data.Deliveries {
+		if d.ID == id && d.HookID == hookID {
+			return *d, nil
+		}
+	}
+	return Delivery{}, ErrDeliveryNotFound
+}
+
+// Deliveries 获取 Hook 的投递历史，按创建时间倒序
+func (s *Store) Deliveries(hookID string) []Delivery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refresh()
+
+	var deliveries []Delivery
+	for _, d := range s.data.Deliveries {
+		if d.HookID == hookID {
+			deliveries = append(deliveries, *d)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+	})
+	return deliveries
+}
+
+// Due 获取到期待投递的记录，skip 中的记录正在投递中
+func (s *Store) Due(now time.Time, limit int, skip map[string]bool) []Delivery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refresh()
+
+	var due []Delivery
+	for _, d := range s.data.Deliveries {
+		if len(due) >= limit {
+			break
+		}
+		if d.Status == StatusPending && !d.NextAttempt.After(now) && !skip[d.ID] {
+			due = append(due, *d)
+		}
+	}
+	return due
+}
+
+// Pending 获取发件箱中待投递记录数量
+func (s *Store) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refresh()
+
+	n := 0
+	for _, d := range s.data.Deliveries {
+		if d.Status == StatusPending {
+			n++
+		}
+	}
+	return n
+}
+
+// prune 仅保留 Hook 最近 historyLimit 条已完成的投递记录，调用方需持有锁
+func (s *Store) prune(hookID string) {
+	if s.historyLimit <= 0 {
+		return
+	}
+	completed := 0
+	for i := len(s.data.Deliveries) - 1; i >= 0; i-- {
+		d := s.data.Deliveries[i]
+		if d.HookID != hookID || d.Status == StatusPending {
+			continue
+		}
+		completed++
+		if completed > s.historyLimit {
+			s.data.Deliveries = append(s.data.Deliveries[:i], s.data.Deliveries[i+1:]...)
+		}
+	}
+}
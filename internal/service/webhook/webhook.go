@@ -0,0 +1,147 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NAS 事件类型
+const (
+	EventUserCreated    = "user.created"
+	EventUserDeleted    = "user.deleted"
+	EventFileUploaded   = "file.uploaded"
+	EventFileDeleted    = "file.deleted"
+	EventShareCreated   = "share.created"
+	EventVolumeLowSpace = "volume.low_space"
+	EventWebhookPing    = "webhook.ping"
+)
+
+// 投递状态
+const (
+	StatusPending = "pending"
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+)
+
+// 请求头
+const (
+	HeaderEvent     = "X-HarborArk-Event"
+	HeaderDelivery  = "X-HarborArk-Delivery"
+	HeaderSignature = "X-HarborArk-Signature"
+	HeaderTimestamp = "X-HarborArk-Timestamp"
+	HeaderRequestID = "X-Request-ID"
+)
+
+// Hook 已注册的 Webhook
+type Hook struct {
+	ID        string    `json:"id" example:"3f2a9c1d7e4b8a60"`
+	URL       string    `json:"url" example:"http://homeassistant.local:8123/api/webhook/nas"`
+	Secret    string    `json:"secret,omitempty"`
+	Events    []string  `json:"events" example:"user.*,file.uploaded"`
+	Enabled   bool      `json:"enabled" example:"true"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Delivery 一次事件投递记录，同时作为持久化发件箱条目
+type Delivery struct {
+	ID             string            `json:"id"`
+	HookID         string            `json:"hook_id"`
+	Event          string            `json:"event"`
+	Payload        json.RawMessage   `json:"payload" swaggertype:"object"`
+	Status         string            `json:"status"`
+	Attempts       int               `json:"attempts"`
+	NextAttempt    time.Time         `json:"next_attempt"`
+	LastError      string            `json:"last_error,omitempty"`
+	ResponseStatus int               `json:"response_status,omitempty"`
+	RedeliveryOf   string            `json:"redelivery_of,omitempty"`
+	RequestID      string            `json:"request_id,omitempty"`
+	TraceContext   map[string]string `json:"trace_context,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	CompletedAt    *time.Time        `json:"completed_at,omitempty"`
+}
+
+// NewHook 创建 Hook，未提供密钥时自动生成
+func NewHook(url, secret string, events []string, enabled bool) *Hook {
+	if secret == "" {
+		secret = NewSecret()
+	}
+	return &Hook{
+		ID:        newID(),
+		URL:       url,
+		Secret:    secret,
+		Events:    events,
+		Enabled:   enabled,
+		CreatedAt: time.Now(),
+	}
+}
+
+// Matches 判断 Hook 是否订阅了指定事件，支持 "*" 与 "user.*" 形式的通配
+func (h *Hook) Matches(event string) bool {
+	if !h.Enabled {
+		return false
+	}
+	for _, filter := range h.Events {
+		switch {
+		case filter == "*" || filter == event:
+			return true
+		case strings.HasSuffix(filter, ".*") && strings.HasPrefix(event, strings.TrimSuffix(filter, "*")):
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultTolerance 接收方允许的签名时间戳与本地时间的最大偏差
+const DefaultTolerance = 5 * time.Minute
+
+// Sign 使用 HMAC-SHA256 对 "<timestamp>.<payload>" 计算签名，格式为 "sha256=<hex>"。
+// timestamp 为 X-HarborArk-Timestamp 请求头中的 Unix 秒数，签名覆盖时间戳，接收方据此拒绝重放的旧请求
+func Sign(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 校验签名，供接收方或测试使用。
+// 时间戳与本地时间相差超过 tolerance（不大于 0 时使用 DefaultTolerance）的请求视为重放，校验失败；
+// 需要完全防止窗口内的重放时，接收方还应记录已处理的 X-HarborArk-Delivery
+func Verify(secret string, payload []byte, timestamp, signature string, tolerance time.Duration) bool {
+	return verifyAt(time.Now(), secret, payload, timestamp, signature, tolerance)
+}
+
+// verifyAt 以 now 为当前时间校验签名
+func verifyAt(now time.Time, secret string, payload []byte, timestamp, signature string, tolerance time.Duration) bool {
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if skew := now.Sub(time.Unix(sec, 0)); skew > tolerance || skew < -tolerance {
+		return false
+	}
+	return hmac.Equal([]byte(Sign(secret, timestamp, payload)), []byte(signature))
+}
+
+// newID 生成随机 ID
+func newID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// NewSecret 生成随机签名密钥
+func NewSecret() string {
+	b := make([]byte, 24)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
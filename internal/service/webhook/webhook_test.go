@@ -0,0 +1,329 @@
+package webhook
+
+import (
+	"HarborArk/config"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	ts := strconv.FormatInt(now.Unix(), 10)
+	payload := []byte(`{"event":"user.created"}`)
+	signature := Sign("secret", ts, payload)
+
+	tests := []struct {
+		name      string
+		secret    string
+		payload   []byte
+		timestamp string
+		signature string
+		now       time.Time
+		tolerance time.Duration
+		want      bool
+	}{
+		{"valid", "secret", payload, ts, signature, now, 0, true},
+		{"within tolerance", "secret", payload, ts, signature, now.Add(4 * time.Minute), 0, true},
+		{"clock behind", "secret", payload, ts, signature, now.Add(-4 * time.Minute), 0, true},
+		{"replayed", "secret", payload, ts, signature, now.Add(6 * time.Minute), 0, false},
+		{"custom tolerance", "secret", payload, ts, signature, now.Add(6 * time.Minute), 10 * time.Minute, true},
+		{"future timestamp", "secret", payload, ts, signature, now.Add(-6 * time.Minute), 0, false},
+		{"tampered body", "secret", []byte(`{"event":"user.deleted"}`), ts, signature, now, 0, false},
+		{"wrong secret", "other", payload, ts, signature, now, 0, false},
+		{"timestamp not signed", "secret", payload, strconv.FormatInt(now.Unix()+1, 10), signature, now, 0, false},
+		{"malformed timestamp", "secret", payload, "yesterday", signature, now, 0, false},
+		{"missing signature", "secret", payload, ts, "", now, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := verifyAt(tt.now, tt.secret, tt.payload, tt.timestamp, tt.signature, tt.tolerance)
+			if got != tt.want {
+				t.Errorf("verifyAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		events  []string
+		enabled bool
+		event   string
+		want    bool
+	}{
+		{[]string{"*"}, true, EventFileUploaded, true},
+		{[]string{"user.*"}, true, EventUserCreated, true},
+		{[]string{"user.*"}, true, EventFileUploaded, false},
+		{[]string{"file.uploaded"}, true, EventFileUploaded, true},
+		{[]string{"file.uploaded"}, true, EventFileDeleted, false},
+		{[]string{"*"}, false, EventFileUploaded, false},
+	}
+	for _, tt := range tests {
+		h := &Hook{Events: tt.events, Enabled: tt.enabled}
+		if got := h.Matches(tt.event); got != tt.want {
+			t.Errorf("Matches(%v, %q) = %v, want %v", tt.events, tt.event, got, tt.want)
+		}
+	}
+}
+
+// received 接收方收到的一次投递
+type received struct {
+	header http.Header
+	body   []byte
+}
+
+func TestDispatcherDelivers(t *testing.T) {
+	got := make(chan received, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got <- received{header: r.Header.Clone(), body: body}
+	}))
+	defer receiver.Close()
+
+	store, err := OpenStore(filepath.Join(t.TempDir(), "webhooks.json"), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hook := NewHook(receiver.URL, "", []string{"user.*"}, true)
+	if err := store.AddHook(hook); err != nil {
+		t.Fatal(err)
+	}
+	d := NewDispatcher(config.WebhookConfig{
+		Workers:        1,
+		MaxAttempts:    1,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Second,
+		Timeout:        5 * time.Second,
+	}, store)
+	d.Start()
+	defer d.Stop(context.Background())
+
+	if err := d.Publish(context.Background(), EventUserCreated, map[string]string{"user": "alice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var r received
+	select {
+	case r = <-got:
+	case <-time.After(5 * time.Second):
+		t.Fatal("接收方未收到投递")
+	}
+	if event := r.header.Get(HeaderEvent); event != EventUserCreated {
+		t.Errorf("%s = %q, want %q", HeaderEvent, event, EventUserCreated)
+	}
+	if r.header.Get(HeaderDelivery) == "" {
+		t.Errorf("缺少 %s", HeaderDelivery)
+	}
+	if !Verify(hook.Secret, r.body, r.header.Get(HeaderTimestamp), r.header.Get(HeaderSignature), 0) {
+		t.Errorf("签名校验失败: timestamp=%q signature=%q", r.header.Get(HeaderTimestamp), r.header.Get(HeaderSignature))
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	d := NewDispatcher(config.WebhookConfig{InitialBackoff: time.Second, MaxBackoff: 10 * time.Second}, nil)
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 8 * time.Second},
+		{5, 10 * time.Second},
+		{20, 10 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := d.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+// flakyReceiver 前 failures 次请求返回 500，之后返回 200，hits 记录收到的请求数
+func flakyReceiver(t *testing.T, failures int32) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) <= failures {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	t.Cleanup(receiver.Close)
+	return receiver, &hits
+}
+
+// newTestDispatcher 创建使用临时存储与毫秒级退避的投递器，并注册一个订阅全部事件的 Hook
+func newTestDispatcher(t *testing.T, path, url string, maxAttempts int) (*Dispatcher, *Hook) {
+	t.Helper()
+	store, err := OpenStore(path, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hook := NewHook(url, "", []string{"*"}, true)
+	if err := store.AddHook(hook); err != nil {
+		t.Fatal(err)
+	}
+	return newDispatcher(store, maxAttempts), hook
+}
+
+// newDispatcher 创建单个投递协程、10ms 起始退避的投递器
+func newDispatcher(store *Store, maxAttempts int) *Dispatcher {
+	return NewDispatcher(config.WebhookConfig{
+		Workers:        1,
+		MaxAttempts:    maxAttempts,
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     40 * time.Millisecond,
+		Timeout:        5 * time.Second,
+	}, store)
+}
+
+// waitDelivery 等待投递记录完成（成功或放弃）
+func waitDelivery(t *testing.T, store *Store, hookID, id string) Delivery {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		item, err := store.Delivery(hookID, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if item.Status != StatusPending {
+			return item
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("投递未完成: %+v", item)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// onlyDelivery 返回 Hook 唯一的投递记录
+func onlyDelivery(t *testing.T, store *Store, hookID string) Delivery {
+	t.Helper()
+	deliveries := store.Deliveries(hookID)
+	if len(deliveries) != 1 {
+		t.Fatalf("Deliveries() = %d 条, want 1", len(deliveries))
+	}
+	return deliveries[0]
+}
+
+func TestDispatcherRetries(t *testing.T) {
+	receiver, hits := flakyReceiver(t, 2)
+	d, hook := newTestDispatcher(t, filepath.Join(t.TempDir(), "webhooks.json"), receiver.URL, 5)
+	d.Start()
+	defer d.Stop(context.Background())
+
+	if err := d.Publish(context.Background(), EventUserCreated, nil); err != nil {
+		t.Fatal(err)
+	}
+	item := waitDelivery(t, d.Store(), hook.ID, onlyDelivery(t, d.Store(), hook.ID).ID)
+	if item.Status != StatusSuccess || item.Attempts != 3 || item.ResponseStatus != http.StatusOK || item.LastError != "" {
+		t.Errorf("失败两次后应重试成功: %+v", item)
+	}
+	if got := hits.Load(); got != 3 {
+		t.Errorf("接收方收到 %d 次请求, want 3", got)
+	}
+}
+
+func TestDispatcherGivesUp(t *testing.T) {
+	receiver, hits := flakyReceiver(t, 100)
+	d, hook := newTestDispatcher(t, filepath.Join(t.TempDir(), "webhooks.json"), receiver.URL, 3)
+	d.Start()
+	defer d.Stop(context.Background())
+
+	if err := d.Publish(context.Background(), EventUserCreated, nil); err != nil {
+		t.Fatal(err)
+	}
+	item := waitDelivery(t, d.Store(), hook.ID, onlyDelivery(t, d.Store(), hook.ID).ID)
+	if item.Status != StatusFailed || item.Attempts != 3 || item.ResponseStatus != http.StatusInternalServerError {
+		t.Errorf("达到最大尝试次数后应放弃: %+v", item)
+	}
+	if item.LastError == "" || item.CompletedAt == nil {
+		t.Errorf("放弃时应记录错误与完成时间: %+v", item)
+	}
+	// 放弃后不再重试
+	time.Sleep(100 * time.Millisecond)
+	if got := hits.Load(); got != 3 {
+		t.Errorf("接收方收到 %d 次请求, want 3", got)
+	}
+}
+
+func TestDispatcherRedeliver(t *testing.T) {
+	receiver, hits := flakyReceiver(t, 1)
+	d, hook := newTestDispatcher(t, filepath.Join(t.TempDir(), "webhooks.json"), receiver.URL, 1)
+	d.Start()
+	defer d.Stop(context.Background())
+
+	if err := d.Publish(context.Background(), EventUserCreated, map[string]string{"user": "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	orig := waitDelivery(t, d.Store(), hook.ID, onlyDelivery(t, d.Store(), hook.ID).ID)
+	if orig.Status != StatusFailed {
+		t.Fatalf("首次投递应失败: %+v", orig)
+	}
+
+	redelivery, err := d.Redeliver(hook.ID, orig.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	item := waitDelivery(t, d.Store(), hook.ID, redelivery.ID)
+	if item.Status != StatusSuccess || item.RedeliveryOf != orig.ID || string(item.Payload) != string(orig.Payload) {
+		t.Errorf("重新投递应以相同负载成功: %+v", item)
+	}
+	if got := hits.Load(); got != 2 {
+		t.Errorf("接收方收到 %d 次请求, want 2", got)
+	}
+
+	if _, err := d.Redeliver(hook.ID, "missing"); !errors.Is(err, ErrDeliveryNotFound) {
+		t.Errorf("Redeliver(missing) error = %v, want ErrDeliveryNotFound", err)
+	}
+	if _, err := d.Redeliver("missing", orig.ID); !errors.Is(err, ErrHookNotFound) {
+		t.Errorf("Redeliver() error = %v, want ErrHookNotFound", err)
+	}
+}
+
+func TestDispatcherResumes(t *testing.T) {
+	receiver, hits := flakyReceiver(t, 0)
+	path := filepath.Join(t.TempDir(), "webhooks.json")
+	d, hook := newTestDispatcher(t, path, receiver.URL, 1)
+	d.Start()
+	if err := d.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// 停止后发布的事件留在发件箱中
+	if err := d.Publish(context.Background(), EventUserCreated, nil); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if got := hits.Load(); got != 0 {
+		t.Fatalf("停止后不应投递，接收方收到 %d 次请求", got)
+	}
+
+	// 重新打开存储后继续投递
+	store, err := OpenStore(path, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := store.Pending(); got != 1 {
+		t.Fatalf("Pending() = %d, want 1", got)
+	}
+	resumed := newDispatcher(store, 1)
+	resumed.Start()
+	defer resumed.Stop(context.Background())
+
+	item := waitDelivery(t, store, hook.ID, onlyDelivery(t, store, hook.ID).ID)
+	if item.Status != StatusSuccess || item.Attempts != 1 {
+		t.Errorf("恢复后应投递成功: %+v", item)
+	}
+	if got := hits.Load(); got != 1 {
+		t.Errorf("接收方收到 %d 次请求, want 1", got)
+	}
+}
@@ -0,0 +1,24 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnsupported 当前平台不支持获取磁盘容量，可用 errors.Is(err, errors.ErrUnsupported) 判断
+var ErrUnsupported = fmt.Errorf("当前平台不支持获取磁盘容量: %w", errors.ErrUnsupported)
+
+// Usage 存储卷容量信息，单位为字节
+type Usage struct {
+	Total uint64 `json:"total"`
+	Free  uint64 `json:"free"`
+	Used  uint64 `json:"used"`
+}
+
+// FreePercent 可用空间百分比
+func (u Usage) FreePercent() float64 {
+	if u.Total == 0 {
+		return 0
+	}
+	return float64(u.Free) / float64(u.Total) * 100
+}
@@ -0,0 +1,22 @@
+//go:build openbsd
+
+package storage
+
+import "golang.org/x/sys/unix"
+
+// DiskUsage 获取路径所在文件系统的容量信息
+func DiskUsage(path string) (Usage, error) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return Usage{}, err
+	}
+	bsize := uint64(st.F_bsize)
+	// F_bavail 为有符号数，超额使用保留空间时可能为负
+	avail := max(st.F_bavail, 0)
+	total := st.F_blocks * bsize
+	return Usage{
+		Total: total,
+		Free:  uint64(avail) * bsize,
+		Used:  total - st.F_bfree*bsize,
+	}, nil
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin && !freebsd && !openbsd && !windows
+
+package storage
+
+// DiskUsage 当前平台不支持获取磁盘容量，始终返回 ErrUnsupported
+func DiskUsage(path string) (Usage, error) {
+	return Usage{}, ErrUnsupported
+}
@@ -0,0 +1,23 @@
+//go:build linux || darwin || freebsd
+
+package storage
+
+import "golang.org/x/sys/unix"
+
+// DiskUsage 获取路径所在文件系统的容量信息。
+// Statfs_t 各字段的类型随系统与架构不同，统一转换为 uint64 计算
+func DiskUsage(path string) (Usage, error) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return Usage{}, err
+	}
+	bsize := uint64(st.Bsize)
+	// FreeBSD 的 Bavail 为有符号数，超额使用保留空间时可能为负
+	avail := max(int64(st.Bavail), 0)
+	total := uint64(st.Blocks) * bsize
+	return Usage{
+		Total: total,
+		Free:  uint64(avail) * bsize,
+		Used:  total - uint64(st.Bfree)*bsize,
+	}, nil
+}
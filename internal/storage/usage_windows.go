@@ -0,0 +1,24 @@
+//go:build windows
+
+package storage
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// DiskUsage 获取路径所在卷的容量信息
+func DiskUsage(path string) (Usage, error) {
+	p, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return Usage{}, err
+	}
+	var free, total, totalFree uint64
+	if err := windows.GetDiskFreeSpaceEx(p, &free, &total, &totalFree); err != nil {
+		return Usage{}, err
+	}
+	return Usage{
+		Total: total,
+		Free:  free,
+		Used:  total - totalFree,
+	}, nil
+}
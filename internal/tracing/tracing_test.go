@@ -0,0 +1,156 @@
+package tracing
+
+import (
+	"HarborArk/config"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// collector 接收 OTLP/HTTP protobuf 请求的采集器桩
+type collector struct {
+	mu    sync.Mutex
+	spans []*tracepb.Span
+	names map[string]string // Span 名称到 service.name
+}
+
+// newCollector 启动采集器桩，返回其地址与收到的 Span
+func newCollector(t *testing.T) (string, *collector) {
+	t.Helper()
+	c := &collector{names: make(map[string]string)}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" {
+			http.NotFound(w, r)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var req coltracepb.ExportTraceServiceRequest
+		if err := proto.Unmarshal(body, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		for _, rs := range req.ResourceSpans {
+			service := ""
+			for _, attr := range rs.Resource.GetAttributes() {
+				if attr.Key == "service.name" {
+					service = attr.Value.GetStringValue()
+				}
+			}
+			for _, ss := range rs.ScopeSpans {
+				for _, span := range ss.Spans {
+					c.spans = append(c.spans, span)
+					c.names[span.Name] = service
+				}
+			}
+		}
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		data, _ := proto.Marshal(&coltracepb.ExportTraceServiceResponse{})
+		w.Write(data)
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL, c
+}
+
+// restoreGlobals 测试结束后恢复全局 TracerProvider 与传播器
+func restoreGlobals(t *testing.T) {
+	provider, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagator)
+	})
+}
+
+func TestExport(t *testing.T) {
+	tests := []struct {
+		name   string
+		ratio  float64
+		err    error
+		want   bool
+		status tracepb.Status_StatusCode
+	}{
+		{"sampled", 1, nil, true, tracepb.Status_STATUS_CODE_UNSET},
+		{"error status", 1, errors.New("disk full"), true, tracepb.Status_STATUS_CODE_ERROR},
+		{"not sampled", 0, nil, false, tracepb.Status_STATUS_CODE_UNSET},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restoreGlobals(t)
+			url, c := newCollector(t)
+			shutdown, err := Init(config.TracingConfig{
+				Enabled:     true,
+				ServiceName: "harborark-test",
+				Endpoint:    url + "/v1/traces",
+				SampleRatio: tt.ratio,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx, span := Start(context.Background(), "volume.scan")
+			if tt.want && TraceID(ctx) == "" {
+				t.Error("TraceID() 为空")
+			}
+			End(span, tt.err)
+			if err := shutdown(context.Background()); err != nil {
+				t.Fatalf("shutdown: %v", err)
+			}
+
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			if !tt.want {
+				if len(c.spans) != 0 {
+					t.Errorf("采集器收到 %d 个 Span，期望 0", len(c.spans))
+				}
+				return
+			}
+			if len(c.spans) != 1 {
+				t.Fatalf("采集器收到 %d 个 Span，期望 1", len(c.spans))
+			}
+			got := c.spans[0]
+			if got.Name != "volume.scan" {
+				t.Errorf("Span 名称 = %q, want volume.scan", got.Name)
+			}
+			if service := c.names[got.Name]; service != "harborark-test" {
+				t.Errorf("service.name = %q, want harborark-test", service)
+			}
+			if code := got.GetStatus().GetCode(); code != tt.status {
+				t.Errorf("Span 状态 = %v, want %v", code, tt.status)
+			}
+		})
+	}
+}
+
+func TestInjectExtract(t *testing.T) {
+	restoreGlobals(t)
+	shutdown, err := Init(config.TracingConfig{Enabled: false})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shutdown(context.Background())
+
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	ctx := Extract(context.Background(), map[string]string{"traceparent": traceparent})
+	if got := TraceID(ctx); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("TraceID() = %q", got)
+	}
+	if got := Inject(ctx)["traceparent"]; got != traceparent {
+		t.Errorf("Inject() traceparent = %q, want %q", got, traceparent)
+	}
+	if got := Inject(context.Background()); got != nil {
+		t.Errorf("Inject() 无追踪上下文时 = %v, want nil", got)
+	}
+	if got := Extract(context.Background(), nil); TraceID(got) != "" {
+		t.Error("Extract(nil) 不应产生追踪上下文")
+	}
+}
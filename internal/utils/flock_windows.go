@@ -0,0 +1,15 @@
+//go:build windows
+
+package utils
+
+import "os"
+
+// LockFile Windows 下不支持平滑重启，不会有多个进程同时写同一文件，无需加锁
+func LockFile(f *os.File) error {
+	return nil
+}
+
+// UnlockFile 释放文件锁
+func UnlockFile(f *os.File) error {
+	return nil
+}
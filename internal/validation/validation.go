@@ -0,0 +1,173 @@
+package validation
+
+import (
+	"HarborArk/internal/i18n"
+	"HarborArk/internal/response"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/zh"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	zhTranslations "github.com/go-playground/validator/v10/translations/zh"
+)
+
+// FieldError 单个字段的校验错误
+type FieldError struct {
+	// Field 字段在请求体中的路径，如 name、items[0].id
+	Field string `json:"field" example:"age"`
+	// Tag 未通过的校验规则
+	Tag string `json:"tag" example:"gte"`
+	// Param 规则参数
+	Param   string `json:"param,omitempty" example:"0"`
+	Message string `json:"message" example:"age必须大于或等于0"`
+}
+
+// customRules 默认翻译中缺少的校验规则，说明见语言包的 validation.rule
+var customRules = []string{"http_url"}
+
+var (
+	setupOnce sync.Once
+	universal *ut.UniversalTranslator
+)
+
+// translator 返回语言对应的校验器翻译，zh-CN 对应 zh
+func translator(lang string) ut.Translator {
+	base, _, _ := strings.Cut(lang, "-")
+	trans, _ := universal.GetTranslator(base)
+	return trans
+}
+
+// Setup 配置 Gin 的校验器：错误中的字段名使用 json 标签，并注册中英文错误信息。
+// 需在处理请求前调用，重复调用无副作用
+func Setup() {
+	setupOnce.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+		v.RegisterTagNameFunc(func(f reflect.StructField) string {
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return f.Name
+			}
+			return name
+		})
+
+		zhLocale, enLocale := zh.New(), en.New()
+		universal = ut.New(zhLocale, zhLocale, enLocale)
+		_ = zhTranslations.RegisterDefaultTranslations(v, translator(i18n.ZhCN))
+		_ = enTranslations.RegisterDefaultTranslations(v, translator(i18n.EnUS))
+		for _, lang := range i18n.Languages {
+			trans := translator(lang)
+			for _, tag := range customRules {
+				text := i18n.Printer(lang).T("validation.rule." + tag)
+				_ = v.RegisterTranslation(tag, trans, func(t ut.Translator) error {
+					return t.Add(tag, text, true)
+				}, func(t ut.Translator, fe validator.FieldError) string {
+					msg, _ := t.T(fe.Tag(), fe.Field(), fe.Param())
+					return msg
+				})
+			}
+		}
+	})
+}
+
+// BindJSON 解析并校验 JSON 请求体，失败时写入错误响应并返回 false
+func BindJSON(c *gin.Context, obj interface{}) bool {
+	if err := c.ShouldBindJSON(obj); err != nil {
+		response.Fail(c, Error(c, err))
+		return false
+	}
+	return true
+}
+
+// Error 将请求体解析或校验失败的错误转换为应用错误，错误信息使用请求的语言。
+// 字段校验失败返回 VALIDATION_FAILED，逐字段的错误放在 data 中
+func Error(c *gin.Context, err error) *response.Error {
+	Setup()
+	p := i18n.Ctx(c.Request.Context())
+
+	var (
+		verrs     validator.ValidationErrors
+		typeErr   *json.UnmarshalTypeError
+		syntaxErr *json.SyntaxError
+	)
+	switch {
+	case errors.As(err, &verrs):
+		trans := translator(string(p))
+		fields := make([]FieldError, 0, len(verrs))
+		for _, fe := range verrs {
+			fields = append(fields, FieldError{
+				Field:   fieldPath(fe.Namespace()),
+				Tag:     fe.Tag(),
+				Param:   fe.Param(),
+				Message: translate(fe, trans, p),
+			})
+		}
+		return response.Validation(p.T("validation.failed")).WithData(fields)
+	case errors.As(err, &typeErr):
+		field := typeErr.Field
+		if field == "" {
+			field = "(root)"
+		}
+		return response.Validation(p.T("validation.failed")).WithData([]FieldError{{
+			Field:   field,
+			Tag:     "type",
+			Param:   typeErr.Type.Kind().String(),
+			Message: p.T("validation.type_mismatch", field, jsonType(typeErr.Type), typeErr.Value),
+		}})
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return response.BadRequest(p.T("validation.invalid_json")).WithDetail(err.Error())
+	case errors.Is(err, io.EOF):
+		return response.BadRequest(p.T("validation.empty_body"))
+	default:
+		return response.BadRequest(p.T("validation.bad_request")).WithDetail(err.Error())
+	}
+}
+
+// translate 返回字段错误的说明，规则没有对应的翻译时使用通用说明
+func translate(fe validator.FieldError, trans ut.Translator, p i18n.Printer) string {
+	if text := fe.Translate(trans); text != fe.Error() {
+		return text
+	}
+	return p.T("validation.rule_failed", fe.Field(), fe.Tag())
+}
+
+// fieldPath 去掉命名空间开头的结构体名，如 User.name 返回 name
+func fieldPath(namespace string) string {
+	if _, path, ok := strings.Cut(namespace, "."); ok {
+		return path
+	}
+	return namespace
+}
+
+// jsonType 返回 Go 类型对应的 JSON 类型名
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
@@ -0,0 +1,20 @@
+package router
+
+import (
+	"HarborArk/internal/i18n"
+	"HarborArk/internal/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupErrors 未匹配的路径与请求方法返回统一的错误响应，而不是 Gin 默认的纯文本
+func SetupErrors(r *gin.Engine) {
+	// 路径存在但方法不匹配时返回 405，Gin 会设置 Allow 响应头
+	r.HandleMethodNotAllowed = true
+	r.NoRoute(func(c *gin.Context) {
+		response.Fail(c, response.NotFound(i18n.Ctx(c.Request.Context()).T("api.error.route_not_found")))
+	})
+	r.NoMethod(func(c *gin.Context) {
+		response.Fail(c, response.MethodNotAllowed(i18n.Ctx(c.Request.Context()).T("api.error.method_not_allowed")))
+	})
+}
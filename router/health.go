@@ -0,0 +1,29 @@
+package router
+
+import (
+	"HarborArk/internal/health"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupHealth 设置存活与就绪检查路由
+func SetupHealth(r *gin.Engine) {
+	r.GET("/livez", healthHandler(health.Liveness))
+	r.GET("/readyz", healthHandler(health.Readiness))
+
+	// 兼容旧的健康检查地址，汇总全部检查项
+	r.GET("/health", healthHandler(health.Liveness|health.Readiness))
+}
+
+// healthHandler 执行检查并按结果返回 200 或 503
+func healthHandler(kind health.Kind) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		report := health.Default().Run(c.Request.Context(), kind)
+		status := http.StatusOK
+		if !report.OK() {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, report)
+	}
+}
@@ -0,0 +1,20 @@
+package router
+
+import (
+	"HarborArk/config"
+	"HarborArk/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupMetrics 设置 Prometheus 指标路由
+func SetupMetrics(r *gin.Engine) {
+	metricsConfig := config.GetMetricsConfig()
+
+	if !metricsConfig.Enabled {
+		return
+	}
+
+	metrics.Init(config.GetStorageConfig().Volumes)
+	r.GET(metricsConfig.Path, gin.WrapH(metrics.Handler()))
+}
@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"HarborArk/config"
+	"HarborArk/internal/logging"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// accessLogTimeLayout Apache 日志的时间格式
+const accessLogTimeLayout = "02/Jan/2006:15:04:05 -0700"
+
+// accessLogRules 访问日志中支持热加载的部分
+type accessLogRules struct {
+	format   string
+	skip     []string
+	sampling []config.AccessLogSampling
+}
+
+var (
+	accessRules atomic.Pointer[accessLogRules]
+	// accessEnabled 访问日志已启用，此时请求日志在应用日志中降为 debug 级别
+	accessEnabled atomic.Bool
+)
+
+// SetAccessLogRules 更新访问日志的格式、跳过列表与采样规则
+func SetAccessLogRules(cfg config.AccessLogConfig) {
+	accessRules.Store(&accessLogRules{
+		format:   cfg.Format,
+		skip:     cfg.Skip,
+		sampling: cfg.Sampling,
+	})
+}
+
+// matchPath 判断路径是否匹配，pattern 以 * 结尾时按前缀匹配
+func matchPath(pattern, path string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(path, prefix)
+	}
+	return path == pattern
+}
+
+// sample 判断请求是否需要记录，返回使用的采样比例
+func (r *accessLogRules) sample(path string, status int) (bool, float64) {
+	for _, pattern := range r.skip {
+		if matchPath(pattern, path) {
+			return false, 0
+		}
+	}
+	for _, rule := range r.sampling {
+		if matchPath(rule.Path, path) {
+			if status >= 400 {
+				return true, 1
+			}
+			return rule.Rate > 0 && rand.Float64() < rule.Rate, rule.Rate
+		}
+	}
+	return true, 1
+}
+
+// accessEntry JSON 格式的一条访问日志
+type accessEntry struct {
+	Time      string  `json:"time"`
+	RemoteIP  string  `json:"remote_ip"`
+	User      string  `json:"user,omitempty"`
+	Method    string  `json:"method"`
+	Path      string  `json:"path"`
+	Query     string  `json:"query,omitempty"`
+	Proto     string  `json:"proto"`
+	Status    int     `json:"status"`
+	BytesIn   int64   `json:"bytes_in"`
+	BytesOut  int64   `json:"bytes_out"`
+	Duration  float64 `json:"duration_ms"`
+	Referer   string  `json:"referer,omitempty"`
+	UserAgent string  `json:"user_agent,omitempty"`
+	RequestID string  `json:"request_id,omitempty"`
+	// SampleRate 采样比例，未采样时省略，统计时每条记录代表 1/SampleRate 个请求
+	SampleRate float64 `json:"sample_rate,omitempty"`
+}
+
+// lineWriter 按行写入，保证并发请求的日志行不交错
+type lineWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lineWriter) writeLine(line []byte) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.w.Write(line) // nolint: errcheck
+}
+
+// AccessLog 按配置的格式写入访问日志，需放在 RequestID 之后、GinRecovery 之前，以便记录 panic 请求的 500 响应
+func AccessLog(cfg config.AccessLogConfig) gin.HandlerFunc {
+	SetAccessLogRules(cfg)
+	accessEnabled.Store(true)
+	var w io.Writer = os.Stdout
+	if cfg.Filename != "stdout" {
+		w = getLogWriter(cfg.Filename, cfg.MaxSize, cfg.MaxBackups, cfg.MaxAge, cfg.Compress)
+	}
+	out := &lineWriter{w: w}
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		body, ok := c.Request.Body.(*countingReader)
+		if !ok && c.Request.Body != nil {
+			body = &countingReader{ReadCloser: c.Request.Body}
+			c.Request.Body = body
+		}
+		c.Next()
+
+		rules := accessRules.Load()
+		status := c.Writer.Status()
+		record, rate := rules.sample(c.Request.URL.Path, status)
+		if !record {
+			return
+		}
+
+		e := accessEntry{
+			Time:      start.Format(time.RFC3339Nano),
+			RemoteIP:  c.ClientIP(),
+			User:      CurrentUser(c),
+			Method:    c.Request.Method,
+			Path:      c.Request.URL.Path,
+			Query:     logging.RedactQuery(c.Request.URL.RawQuery),
+			Proto:     c.Request.Proto,
+			Status:    status,
+			BytesOut:  int64(max(c.Writer.Size(), 0)),
+			Duration:  float64(time.Since(start).Microseconds()) / 1000,
+			Referer:   c.Request.Referer(),
+			UserAgent: c.Request.UserAgent(),
+			RequestID: GetRequestID(c),
+		}
+		if body != nil {
+			e.BytesIn = body.n
+		}
+		if rate < 1 {
+			e.SampleRate = rate
+		}
+		out.writeLine(formatAccessEntry(rules.format, e, start))
+	}
+}
+
+// formatAccessEntry 按格式生成一行访问日志
+func formatAccessEntry(format string, e accessEntry, start time.Time) []byte {
+	if format == "json" {
+		line, _ := json.Marshal(e)
+		return append(line, '\n')
+	}
+
+	// %h %l %u %t "%r" %>s %b
+	uri := e.Path
+	if e.Query != "" {
+		uri += "?" + e.Query
+	}
+	bytesOut := "-"
+	if e.BytesOut > 0 {
+		bytesOut = strconv.FormatInt(e.BytesOut, 10)
+	}
+	line := fmt.Sprintf("%s - %s [%s] \"%s %s %s\" %d %s",
+		e.RemoteIP, orDash(escapeLogValue(e.User)), start.Format(accessLogTimeLayout),
+		e.Method, escapeLogValue(uri), e.Proto, e.Status, bytesOut)
+	if format == "combined" {
+		// "%{Referer}i" "%{User-Agent}i"
+		line += fmt.Sprintf(" \"%s\" \"%s\"", orDash(escapeLogValue(e.Referer)), orDash(escapeLogValue(e.UserAgent)))
+	}
+	return []byte(line + "\n")
+}
+
+// orDash 空值按 Apache 惯例输出为 -
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// escapeLogValue 转义引号、反斜杠与控制字符，防止伪造日志行
+func escapeLogValue(s string) string {
+	quoted := strconv.Quote(s)
+	return quoted[1 : len(quoted)-1]
+}
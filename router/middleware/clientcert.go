@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"HarborArk/config"
+	"HarborArk/internal/i18n"
+	"HarborArk/internal/logging"
+	"HarborArk/internal/response"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ClientCertAuth 将已校验的客户端证书映射为用户。
+// 配置了 clientUsers 时仅允许映射表中的证书访问，否则直接使用证书 CN 作为用户名
+func ClientCertAuth(cfg config.TLSConfig) gin.HandlerFunc {
+	users := make(map[string]config.ClientUserConfig, len(cfg.ClientUsers))
+	for _, u := range cfg.ClientUsers {
+		users[u.CommonName] = u
+	}
+
+	return func(c *gin.Context) {
+		state := c.Request.TLS
+		if state == nil || len(state.VerifiedChains) == 0 {
+			c.Next()
+			return
+		}
+
+		cn := state.VerifiedChains[0][0].Subject.CommonName
+		user, role := cn, ""
+		if len(users) > 0 {
+			mapped, ok := users[cn]
+			if !ok {
+				logging.Ctx(c.Request.Context()).Warn("客户端证书未映射到用户", zap.String("cn", cn), zap.String("ip", c.ClientIP()))
+				response.Fail(c, response.Forbidden(i18n.Ctx(c.Request.Context()).T("api.error.client_cert_unauthorized")))
+				return
+			}
+			user, role = mapped.User, mapped.Role
+		}
+
+		c.Set(ContextUserKey, user)
+		c.Set(ContextRoleKey, role)
+		c.Next()
+	}
+}
+
+// RequireAdmin 只允许 admin 角色访问：未认证时返回 401，其他角色返回 403。需放在 ClientCertAuth 之后
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p := i18n.Ctx(c.Request.Context())
+		switch {
+		case CurrentUser(c) == "":
+			response.Fail(c, response.Unauthorized(p.T("api.auth.required")))
+		case CurrentRole(c) != RoleAdmin:
+			logging.Ctx(c.Request.Context()).Warn("非管理员访问管理接口",
+				zap.String("user", CurrentUser(c)), zap.String("path", c.Request.URL.Path), zap.String("ip", c.ClientIP()))
+			response.Fail(c, response.Forbidden(p.T("api.auth.admin_required")))
+		default:
+			c.Next()
+		}
+	}
+}
+
+// AdminNotConfigured 未配置管理员认证时拦截管理接口，返回 403 并说明如何启用
+func AdminNotConfigured() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p := i18n.Ctx(c.Request.Context())
+		response.Fail(c, response.Forbidden(p.T("api.auth.admin_not_configured")).WithDetail(p.T("api.auth.admin_hint")))
+	}
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import "github.com/gin-gonic/gin"
+
+// ContextUserKey gin.Context 中保存当前用户名的键，由认证相关中间件写入
+const ContextUserKey = "user"
+
+// ContextRoleKey gin.Context 中保存当前用户角色的键
+const ContextRoleKey = "role"
+
+// RoleAdmin 管理员角色，可以访问管理与审计接口
+const RoleAdmin = "admin"
+
+// CurrentUser 获取当前请求的用户名，未认证时返回空字符串
+func CurrentUser(c *gin.Context) string {
+	return c.GetString(ContextUserKey)
+}
+
+// CurrentRole 获取当前请求的用户角色，未认证或未设置角色时返回空字符串
+func CurrentRole(c *gin.Context) string {
+	return c.GetString(ContextRoleKey)
+}
@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"HarborArk/config"
+	"HarborArk/internal/i18n"
+	"HarborArk/internal/response"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// originPattern https://*.example.com 形式的来源，匹配 example.com 的全部子域名
+type originPattern struct {
+	prefix string // scheme://
+	suffix string // .example.com[:port]
+}
+
+// corsRules 按当前配置生成的 CORS 规则
+type corsRules struct {
+	enabled     bool
+	anyOrigin   bool
+	origins     map[string]bool
+	patterns    []originPattern
+	credentials bool
+	methods     string
+	headers     string
+	expose      string
+	maxAge      string
+}
+
+var cors atomic.Pointer[corsRules]
+
+// setCORS 预先生成 CORS 规则
+func setCORS(cfg config.CORSConfig) {
+	r := &corsRules{
+		enabled:     cfg.Enabled,
+		origins:     make(map[string]bool),
+		credentials: cfg.AllowCredentials,
+		methods:     strings.Join(cfg.AllowMethods, ", "),
+		headers:     strings.Join(cfg.AllowHeaders, ", "),
+		expose:      strings.Join(cfg.ExposeHeaders, ", "),
+	}
+	if cfg.MaxAge > 0 {
+		r.maxAge = strconv.Itoa(int(cfg.MaxAge.Seconds()))
+	}
+	for _, origin := range cfg.AllowOrigins {
+		origin = strings.ToLower(origin)
+		if origin == "*" {
+			r.anyOrigin = true
+			continue
+		}
+		if scheme, host, ok := strings.Cut(origin, "://*."); ok {
+			r.patterns = append(r.patterns, originPattern{prefix: scheme + "://", suffix: "." + host})
+			continue
+		}
+		r.origins[origin] = true
+	}
+	cors.Store(r)
+}
+
+// allowed 判断来源是否允许跨域访问
+func (r *corsRules) allowed(origin string) bool {
+	if r.anyOrigin {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	if r.origins[origin] {
+		return true
+	}
+	for _, p := range r.patterns {
+		if !strings.HasPrefix(origin, p.prefix) || !strings.HasSuffix(origin, p.suffix) {
+			continue
+		}
+		sub := origin[len(p.prefix) : len(origin)-len(p.suffix)]
+		if sub != "" && !strings.ContainsAny(sub, "/:@") {
+			return true
+		}
+	}
+	return false
+}
+
+// CORS 按配置响应跨域请求：允许的来源获得 Access-Control-Allow-* 响应头，
+// 预检请求直接返回 204，不允许的来源发起的预检请求返回 403。
+// 需放在限流与认证之前，使预检请求不受其影响
+func CORS(cfg config.CORSConfig) gin.HandlerFunc {
+	setCORS(cfg)
+
+	return func(c *gin.Context) {
+		r := cors.Load()
+		origin := c.GetHeader("Origin")
+		if !r.enabled || origin == "" {
+			c.Next()
+			return
+		}
+
+		h := c.Writer.Header()
+		h.Add("Vary", "Origin")
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		if !r.allowed(origin) {
+			if preflight {
+				response.Fail(c, response.Forbidden(i18n.Ctx(c.Request.Context()).T("api.cors.origin_not_allowed", origin)))
+				return
+			}
+			// 简单请求照常处理，浏览器因缺少 Access-Control-Allow-Origin 而拒绝脚本读取响应
+			c.Next()
+			return
+		}
+
+		if r.anyOrigin && !r.credentials {
+			h.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			h.Set("Access-Control-Allow-Origin", origin)
+		}
+		if r.credentials {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+		if preflight {
+			h.Add("Vary", "Access-Control-Request-Method")
+			h.Add("Vary", "Access-Control-Request-Headers")
+			h.Set("Access-Control-Allow-Methods", r.methods)
+			h.Set("Access-Control-Allow-Headers", r.headers)
+			if r.maxAge != "" {
+				h.Set("Access-Control-Max-Age", r.maxAge)
+			}
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		if r.expose != "" {
+			h.Set("Access-Control-Expose-Headers", r.expose)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"HarborArk/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCORSAllowed(t *testing.T) {
+	setCORS(config.CORSConfig{
+		Enabled:      true,
+		AllowOrigins: []string{"https://nas.example.com", "https://*.example.org", "http://*.lan:8080"},
+	})
+	r := cors.Load()
+
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"https://nas.example.com", true},
+		{"HTTPS://NAS.Example.com", true},
+		{"https://evil.example.com", false},
+		{"http://nas.example.com", false},
+		{"https://a.example.org", true},
+		{"https://a.b.example.org", true},
+		{"https://example.org", false},
+		{"https://.example.org", false},
+		{"http://a.example.org", false},
+		{"https://a.example.org:8443", false},
+		{"https://evilexample.org", false},
+		{"https://example.org.evil.com", false},
+		{"https://user@a.example.org", false},
+		{"https://evil.com/.example.org", false},
+		{"http://nas.lan:8080", true},
+		{"http://nas.lan", false},
+		{"http://nas.lan:9090", false},
+		{"null", false},
+	}
+	for _, tt := range tests {
+		if got := r.allowed(tt.origin); got != tt.want {
+			t.Errorf("allowed(%q) = %v, want %v", tt.origin, got, tt.want)
+		}
+	}
+
+	setCORS(config.CORSConfig{Enabled: true, AllowOrigins: []string{"*"}})
+	if !cors.Load().allowed("https://anything.test") {
+		t.Error("* 应允许任意来源")
+	}
+}
+
+// corsRequest 经过 CORS 中间件发送请求
+func corsRequest(cfg config.CORSConfig, method, origin string, preflight bool) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(CORS(cfg))
+	r.Any("/api/v1/users", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(method, "/api/v1/users", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	if preflight {
+		req.Header.Set("Access-Control-Request-Method", http.MethodDelete)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORS(t *testing.T) {
+	base := config.CORSConfig{
+		Enabled:       true,
+		AllowOrigins:  []string{"https://*.example.com"},
+		AllowMethods:  []string{"GET", "DELETE"},
+		AllowHeaders:  []string{"Content-Type", "X-CSRF-Token"},
+		ExposeHeaders: []string{"X-Request-ID"},
+		MaxAge:        10 * time.Minute,
+	}
+	withCredentials := base
+	withCredentials.AllowCredentials = true
+	anyOrigin := base
+	anyOrigin.AllowOrigins = []string{"*"}
+	disabled := base
+	disabled.Enabled = false
+
+	tests := []struct {
+		name        string
+		cfg         config.CORSConfig
+		method      string
+		origin      string
+		preflight   bool
+		status      int
+		allowOrigin string
+		headers     map[string]string
+	}{
+		{
+			name: "preflight", cfg: base, method: http.MethodOptions, origin: "https://nas.example.com", preflight: true,
+			status: http.StatusNoContent, allowOrigin: "https://nas.example.com",
+			headers: map[string]string{
+				"Access-Control-Allow-Methods": "GET, DELETE",
+				"Access-Control-Allow-Headers": "Content-Type, X-CSRF-Token",
+				"Access-Control-Max-Age":       "600",
+			},
+		},
+		{
+			name: "preflight from other origin", cfg: base, method: http.MethodOptions, origin: "https://evil.test", preflight: true,
+			status: http.StatusForbidden,
+		},
+		{
+			name: "simple request", cfg: base, method: http.MethodGet, origin: "https://nas.example.com",
+			status: http.StatusOK, allowOrigin: "https://nas.example.com",
+			headers: map[string]string{"Access-Control-Expose-Headers": "X-Request-ID"},
+		},
+		{
+			name: "simple request from other origin", cfg: base, method: http.MethodGet, origin: "https://evil.test",
+			status: http.StatusOK,
+		},
+		{
+			name: "credentials", cfg: withCredentials, method: http.MethodGet, origin: "https://nas.example.com",
+			status: http.StatusOK, allowOrigin: "https://nas.example.com",
+			headers: map[string]string{"Access-Control-Allow-Credentials": "true"},
+		},
+		{
+			name: "any origin", cfg: anyOrigin, method: http.MethodGet, origin: "https://evil.test",
+			status: http.StatusOK, allowOrigin: "*",
+		},
+		{
+			name: "same origin", cfg: base, method: http.MethodGet,
+			status: http.StatusOK,
+		},
+		{
+			name: "disabled", cfg: disabled, method: http.MethodGet, origin: "https://nas.example.com",
+			status: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := corsRequest(tt.cfg, tt.method, tt.origin, tt.preflight)
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.allowOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.allowOrigin)
+			}
+			for name, want := range tt.headers {
+				if got := w.Header().Get(name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"HarborArk/config"
+	"HarborArk/internal/i18n"
+	"HarborArk/internal/response"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"slices"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// csrfTokenBytes CSRF 令牌的随机字节数
+const csrfTokenBytes = 32
+
+var csrf atomic.Pointer[config.CSRFConfig]
+
+// setCSRF 更新 CSRF 防护配置
+func setCSRF(cfg config.CSRFConfig) {
+	csrf.Store(&cfg)
+}
+
+// newCSRFToken 生成随机令牌
+func newCSRFToken() string {
+	b := make([]byte, csrfTokenBytes)
+	rand.Read(b) // nolint: errcheck
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// validCSRFToken 判断 Cookie 中的令牌格式是否正确，不正确时重新签发
+func validCSRFToken(token string) bool {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	return err == nil && len(b) == csrfTokenBytes
+}
+
+// cookieAuthenticated 判断请求是否通过 Cookie 认证，只有这类请求会被跨站伪造
+func cookieAuthenticated(r *http.Request, cfg *config.CSRFConfig) bool {
+	for _, cookie := range r.Cookies() {
+		if cookie.Name == cfg.CookieName {
+			continue
+		}
+		if len(cfg.SessionCookies) == 0 || slices.Contains(cfg.SessionCookies, cookie.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameSiteModes 配置值对应的 SameSite 属性
+var sameSiteModes = map[string]http.SameSite{
+	"strict": http.SameSiteStrictMode,
+	"lax":    http.SameSiteLaxMode,
+	"none":   http.SameSiteNoneMode,
+}
+
+// CSRF 双重提交 Cookie 防护：在 Cookie 与同名响应头中下发随机令牌，
+// 通过 Cookie 认证的 POST、PUT、PATCH、DELETE 请求必须在请求头中提交与 Cookie 相同的令牌，否则返回 403。
+// 使用 Authorization 头或客户端证书认证的请求不受影响
+func CSRF(cfg config.CSRFConfig) gin.HandlerFunc {
+	setCSRF(cfg)
+
+	return func(c *gin.Context) {
+		cfg := csrf.Load()
+		if !cfg.Enabled {
+			c.Next()
+			return
+		}
+
+		// token 为请求携带的令牌，current 为响应下发的令牌
+		token, _ := c.Cookie(cfg.CookieName)
+		current := token
+		if !validCSRFToken(token) {
+			token, current = "", newCSRFToken()
+			sameSite := sameSiteModes[cfg.SameSite]
+			// 脚本需要读取令牌放入请求头，因此不设置 HttpOnly
+			http.SetCookie(c.Writer, &http.Cookie{
+				Name:     cfg.CookieName,
+				Value:    current,
+				Path:     "/",
+				MaxAge:   int(cfg.MaxAge.Seconds()),
+				Secure:   c.Request.TLS != nil || sameSite == http.SameSiteNoneMode,
+				SameSite: sameSite,
+			})
+		}
+		// 跨域的前端无法读取 API 域名下的 Cookie，通过响应头获取令牌
+		c.Header(cfg.HeaderName, current)
+
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+			c.Next()
+			return
+		}
+		if !cookieAuthenticated(c.Request, cfg) {
+			c.Next()
+			return
+		}
+		sent := c.GetHeader(cfg.HeaderName)
+		if token == "" || subtle.ConstantTimeCompare([]byte(sent), []byte(token)) != 1 {
+			response.Fail(c, response.New(http.StatusForbidden, response.ReasonCSRF,
+				i18n.Ctx(c.Request.Context()).T("api.csrf.invalid", cfg.HeaderName, cfg.CookieName)))
+			return
+		}
+		c.Next()
+	}
+}
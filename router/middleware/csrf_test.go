@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"HarborArk/config"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// csrfConfig 测试用的 CSRF 配置
+func csrfConfig(sessionCookies ...string) config.CSRFConfig {
+	return config.CSRFConfig{
+		Enabled:        true,
+		CookieName:     "csrf_token",
+		HeaderName:     "X-CSRF-Token",
+		SessionCookies: sessionCookies,
+		SameSite:       "strict",
+		MaxAge:         12 * time.Hour,
+	}
+}
+
+// csrfRequest 经过 CSRF 中间件发送请求，cookies 为请求携带的 Cookie，header 为提交的令牌
+func csrfRequest(cfg config.CSRFConfig, method string, cookies map[string]string, header string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(CSRF(cfg))
+	r.Any("/api/v1/users", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(method, "/api/v1/users", nil)
+	for name, value := range cookies {
+		req.AddCookie(&http.Cookie{Name: name, Value: value})
+	}
+	if header != "" {
+		req.Header.Set(cfg.HeaderName, header)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestCSRF(t *testing.T) {
+	token := newCSRFToken()
+	other := newCSRFToken()
+
+	tests := []struct {
+		name    string
+		cfg     config.CSRFConfig
+		method  string
+		cookies map[string]string
+		header  string
+		status  int
+	}{
+		{"safe method", csrfConfig(), http.MethodGet, map[string]string{"session": "s"}, "", http.StatusOK},
+		{"matching token", csrfConfig(), http.MethodPost, map[string]string{"session": "s", "csrf_token": token}, token, http.StatusOK},
+		{"missing header", csrfConfig(), http.MethodPost, map[string]string{"session": "s", "csrf_token": token}, "", http.StatusForbidden},
+		{"mismatched token", csrfConfig(), http.MethodDelete, map[string]string{"session": "s", "csrf_token": token}, other, http.StatusForbidden},
+		{"header without cookie", csrfConfig(), http.MethodPut, map[string]string{"session": "s"}, token, http.StatusForbidden},
+		{"malformed cookie", csrfConfig(), http.MethodPatch, map[string]string{"session": "s", "csrf_token": "abc"}, "abc", http.StatusForbidden},
+		{"no cookie authentication", csrfConfig(), http.MethodPost, nil, "", http.StatusOK},
+		{"only token cookie", csrfConfig(), http.MethodPost, map[string]string{"csrf_token": token}, "", http.StatusOK},
+		{"session cookie listed", csrfConfig("sid"), http.MethodPost, map[string]string{"sid": "s"}, "", http.StatusForbidden},
+		{"other cookie not listed", csrfConfig("sid"), http.MethodPost, map[string]string{"theme": "dark"}, "", http.StatusOK},
+		{"disabled", config.CSRFConfig{}, http.MethodPost, map[string]string{"session": "s"}, "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := csrfRequest(tt.cfg, tt.method, tt.cookies, tt.header)
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+		})
+	}
+}
+
+func TestCSRFIssuesToken(t *testing.T) {
+	cfg := csrfConfig()
+
+	// 没有有效令牌时签发新令牌，并在响应头中返回同一令牌
+	w := csrfRequest(cfg, http.MethodGet, map[string]string{"csrf_token": "abc"}, "")
+	cookie := w.Header().Get("Set-Cookie")
+	issued := w.Header().Get(cfg.HeaderName)
+	if !validCSRFToken(issued) {
+		t.Fatalf("%s = %q, 不是有效的令牌", cfg.HeaderName, issued)
+	}
+	for _, want := range []string{"csrf_token=" + issued, "Path=/", "Max-Age=43200", "SameSite=Strict"} {
+		if !strings.Contains(cookie, want) {
+			t.Errorf("Set-Cookie = %q, 缺少 %q", cookie, want)
+		}
+	}
+	if strings.Contains(cookie, "HttpOnly") {
+		t.Error("令牌 Cookie 需要脚本可读，不应设置 HttpOnly")
+	}
+
+	// 已有有效令牌时沿用，不再设置 Cookie
+	w = csrfRequest(cfg, http.MethodGet, map[string]string{"csrf_token": issued}, "")
+	if got := w.Header().Get("Set-Cookie"); got != "" {
+		t.Errorf("Set-Cookie = %q, want empty", got)
+	}
+	if got := w.Header().Get(cfg.HeaderName); got != issued {
+		t.Errorf("%s = %q, want %q", cfg.HeaderName, got, issued)
+	}
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"HarborArk/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Locale 根据 Accept-Language 选择响应消息的语言，写入请求 context 与 Content-Language 响应头。
+// 需放在其他会返回错误响应的中间件之前
+func Locale() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := i18n.Match(c.GetHeader("Accept-Language"))
+		c.Header("Content-Language", lang)
+		c.Header("Vary", "Accept-Language")
+		c.Request = c.Request.WithContext(i18n.WithLanguage(c.Request.Context(), lang))
+		c.Next()
+	}
+}
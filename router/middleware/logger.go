@@ -2,10 +2,15 @@ package middleware
 
 import (
 	"HarborArk/config"
+	"HarborArk/internal/i18n"
+	"HarborArk/internal/logging"
+	"HarborArk/internal/logging/sink"
+	"HarborArk/internal/response"
+	"HarborArk/internal/tracing"
+	"bytes"
 	"fmt"
+	"io"
 	"net"
-	"net/http"
-	"net/http/httputil"
 	"os"
 	"runtime/debug"
 	"strings"
@@ -17,7 +22,11 @@ import (
 	"gopkg.in/natefinch/lumberjack.v2"
 )
 
-var lg *zap.Logger
+var (
+	lg *zap.Logger
+	// sinks 日志文件之外的输出
+	sinks []*sink.Sink
+)
 
 // Init 初始化日志器
 func Init(cfg config.LogConfig, mode string) (err error) {
@@ -26,28 +35,42 @@ func Init(cfg config.LogConfig, mode string) (err error) {
 		return fmt.Errorf("创建日志目录失败: %v", err)
 	}
 
-	writeSyncer := getLogWriter(cfg.Filename, cfg.MaxSize, cfg.MaxBackups, cfg.MaxAge)
+	writeSyncer := getLogWriter(cfg.Filename, cfg.MaxSize, cfg.MaxBackups, cfg.MaxAge, cfg.Compress)
 	encoder := getEncoder(cfg.Encoding)
 
-	var level zapcore.Level
-	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
-		return fmt.Errorf("解析日志级别失败: %v", err)
+	if err := logging.SetLevel(cfg.Level); err != nil {
+		return err
+	}
+	for name, text := range cfg.Levels {
+		if err := logging.SetLoggerLevel(name, text); err != nil {
+			return err
+		}
 	}
 
-	var core zapcore.Core
-	if mode == "debug" || mode == "dev" {
-		// 开发模式：同时输出到文件和控制台
-		consoleEncoder := getConsoleEncoder()
-		core = zapcore.NewTee(
-			zapcore.NewCore(encoder, writeSyncer, level),                                 // 文件输出
-			zapcore.NewCore(consoleEncoder, zapcore.Lock(os.Stdout), zapcore.DebugLevel), // 控制台输出
-		)
-	} else {
-		// 生产模式：仅输出到文件
-		core = zapcore.NewCore(encoder, writeSyncer, level)
+	// 级别由 logging 统一控制，各输出接收全部级别或自身设置的更高级别
+	cores := []zapcore.Core{zapcore.NewCore(encoder, writeSyncer, zapcore.DebugLevel)} // 文件输出
+	stdout := false
+	for i, sinkConfig := range cfg.Sinks {
+		s, err := sink.New(sinkConfig, i, getEncoderConfig())
+		if err != nil {
+			closeSinks()
+			return err
+		}
+		sinks = append(sinks, s)
+		cores = append(cores, s.Core)
+		stdout = stdout || sinkConfig.Type == "stdout"
+	}
+	if (mode == "debug" || mode == "dev") && !stdout {
+		// 开发模式：未配置 stdout 输出时同时输出到控制台
+		cores = append(cores, zapcore.NewCore(getConsoleEncoder(), zapcore.Lock(os.Stdout), zapcore.DebugLevel))
+	}
+
+	SetRedaction(cfg.Redact)
+	for i := range cores {
+		cores[i] = logging.Redact(cores[i])
 	}
 
-	lg = zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel))
+	lg = zap.New(logging.NewCore(zapcore.NewTee(cores...)), zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel))
 	zap.ReplaceGlobals(lg)
 
 	lg.Info("日志系统初始化成功",
@@ -55,11 +78,49 @@ func Init(cfg config.LogConfig, mode string) (err error) {
 		zap.String("encoding", cfg.Encoding),
 		zap.String("filename", cfg.Filename),
 		zap.String("mode", mode),
+		zap.Strings("sinks", sinkNames()),
 	)
 
 	return nil
 }
 
+// SetRedaction 设置日志脱敏规则，支持热加载
+func SetRedaction(cfg config.RedactConfig) {
+	logging.SetRedaction(logging.RedactRules{
+		Headers: cfg.Headers,
+		Query:   cfg.Query,
+		Fields:  cfg.Fields,
+		Mask:    cfg.Mask,
+	})
+}
+
+// Sync 刷新日志缓冲并关闭各输出，退出前调用
+func Sync() error {
+	if lg == nil {
+		return nil
+	}
+	err := lg.Sync()
+	closeSinks()
+	return err
+}
+
+// closeSinks 关闭日志文件之外的输出，推送缓冲中剩余的日志
+func closeSinks() {
+	for _, s := range sinks {
+		s.Close()
+	}
+	sinks = nil
+}
+
+// sinkNames 返回已启用输出的名称
+func sinkNames() []string {
+	names := make([]string, 0, len(sinks))
+	for _, s := range sinks {
+		names = append(names, s.Name)
+	}
+	return names
+}
+
 // getEncoder 获取编码器
 func getEncoder(encoding string) zapcore.Encoder {
 	encoderConfig := getEncoderConfig()
@@ -97,12 +158,13 @@ func getEncoderConfig() zapcore.EncoderConfig {
 	return encoderConfig
 }
 
-func getLogWriter(filename string, maxSize, maxBackup, maxAge int) zapcore.WriteSyncer {
+func getLogWriter(filename string, maxSize, maxBackup, maxAge int, compress bool) zapcore.WriteSyncer {
 	lumberJackLogger := &lumberjack.Logger{
 		Filename:   filename,
 		MaxSize:    maxSize,
 		MaxBackups: maxBackup,
 		MaxAge:     maxAge,
+		Compress:   compress,
 	}
 	return zapcore.AddSync(lumberJackLogger)
 }
@@ -116,7 +178,13 @@ func GinLogger() gin.HandlerFunc {
 		c.Next()
 
 		cost := time.Since(start)
-		lg.Info(path,
+		level := zapcore.InfoLevel
+		if accessEnabled.Load() {
+			// 已有单独的访问日志
+			level = zapcore.DebugLevel
+		}
+		// query 中的敏感参数在写入前由 logging.Redact 隐藏
+		logging.Ctx(c.Request.Context()).Log(level, path,
 			zap.Int("status", c.Writer.Status()),
 			zap.String("method", c.Request.Method),
 			zap.String("path", path),
@@ -125,6 +193,7 @@ func GinLogger() gin.HandlerFunc {
 			zap.String("user-agent", c.Request.UserAgent()),
 			zap.String("errors", c.Errors.ByType(gin.ErrorTypePrivate).String()),
 			zap.Duration("cost", cost),
+			zap.String("trace_id", tracing.TraceID(c.Request.Context())),
 		)
 	}
 }
@@ -145,11 +214,13 @@ func GinRecovery(stack bool) gin.HandlerFunc {
 					}
 				}
 
-				httpRequest, _ := httputil.DumpRequest(c.Request, false)
+				// 请求头中的 Authorization、Cookie 等敏感信息已隐藏
+				httpRequest := logging.DumpRequest(c.Request)
+				l := logging.Ctx(c.Request.Context())
 				if brokenPipe {
-					lg.Error(c.Request.URL.Path,
+					l.Error(c.Request.URL.Path,
 						zap.Any("error", err),
-						zap.String("request", string(httpRequest)),
+						zap.String("request", httpRequest),
 					)
 					// If the connection is dead, we can't write a status to it.
 					c.Error(err.(error)) // nolint: errcheck
@@ -157,21 +228,82 @@ func GinRecovery(stack bool) gin.HandlerFunc {
 					return
 				}
 
+				fields := []zap.Field{
+					zap.Any("error", err),
+					zap.String("request", httpRequest),
+					zap.String("trace_id", tracing.TraceID(c.Request.Context())),
+				}
 				if stack {
-					lg.Error("[Recovery from panic]",
-						zap.Any("error", err),
-						zap.String("request", string(httpRequest)),
-						zap.String("stack", string(debug.Stack())),
-					)
-				} else {
-					lg.Error("[Recovery from panic]",
-						zap.Any("error", err),
-						zap.String("request", string(httpRequest)),
-					)
+					fields = append(fields, zap.String("stack", string(debug.Stack())))
 				}
-				c.AbortWithStatus(http.StatusInternalServerError)
+				l.Error("[Recovery from panic]", fields...)
+				// 响应中包含请求 ID，便于按 ID 查找上面的日志
+				response.Fail(c, response.Internal(i18n.Ctx(c.Request.Context()).T("api.error.internal")))
 			}
 		}()
 		c.Next()
 	}
 }
+
+// DebugLogging 为匹配临时调试规则的用户或路径启用调试日志，需放在认证中间件之后
+func DebugLogging() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rule, ok := logging.MatchDebug(CurrentUser(c), c.Request.URL.Path)
+		if !ok {
+			c.Next()
+			return
+		}
+		ctx := c.Request.Context()
+		l := logging.ForceDebug(logging.Ctx(ctx)).With(zap.String("debug_rule", rule.ID))
+		c.Request = c.Request.WithContext(logging.WithContext(ctx, l))
+		fields := []zap.Field{
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.String("query", c.Request.URL.RawQuery),
+			zap.String("user", CurrentUser(c)),
+			zap.Int64("content-length", c.Request.ContentLength),
+			zap.String("content-type", c.ContentType()),
+			zap.Any("headers", logging.RedactHeader(c.Request.Header)),
+		}
+		if body, ok := debugBody(c); ok {
+			fields = append(fields, zap.String("body", body))
+		}
+		l.Debug("请求详情", fields...)
+		c.Next()
+	}
+}
+
+// maxDebugBody 调试日志中记录的请求体最大长度
+const maxDebugBody = 4 << 10
+
+// debugBody 读取 JSON 或表单请求体的开头部分并隐藏敏感字段，读取后恢复请求体供后续处理。
+// 其他类型以及超过长度无法完整解析的 JSON 不记录
+func debugBody(c *gin.Context) (string, bool) {
+	contentType := c.ContentType()
+	if c.Request.Body == nil || (contentType != gin.MIMEJSON && contentType != gin.MIMEPOSTForm) {
+		return "", false
+	}
+	data, err := io.ReadAll(io.LimitReader(c.Request.Body, maxDebugBody+1))
+	c.Request.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(data), c.Request.Body), c.Request.Body}
+	if err != nil || len(data) == 0 {
+		return "", false
+	}
+
+	if contentType == gin.MIMEPOSTForm {
+		if len(data) > maxDebugBody {
+			data = data[:maxDebugBody]
+		}
+		return logging.RedactQuery(string(data)), true
+	}
+	if len(data) > maxDebugBody {
+		return fmt.Sprintf("(超过 %d 字节，未记录)", maxDebugBody), true
+	}
+	redacted, ok := logging.RedactJSON(data)
+	if !ok {
+		return "(不是合法的 JSON，未记录)", true
+	}
+	return string(redacted), true
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"HarborArk/internal/metrics"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// countingReader 统计请求体读取的字节数
+type countingReader struct {
+	io.ReadCloser
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// GinMetrics 按路由模板与状态码记录请求数、耗时及上传下载字节数
+func GinMetrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		// 与 AccessLog 共用同一个计数器
+		body, ok := c.Request.Body.(*countingReader)
+		if !ok && c.Request.Body != nil {
+			body = &countingReader{ReadCloser: c.Request.Body}
+			c.Request.Body = body
+		}
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.ObserveRequest(c.Request.Method, route, strconv.Itoa(c.Writer.Status()), time.Since(start))
+		if body != nil {
+			metrics.AddUploadBytes(body.n)
+		}
+		metrics.AddDownloadBytes(int64(c.Writer.Size()))
+	}
+}
@@ -0,0 +1,207 @@
+package middleware
+
+import (
+	"HarborArk/config"
+	"HarborArk/internal/i18n"
+	"HarborArk/internal/logging"
+	"HarborArk/internal/metrics"
+	"HarborArk/internal/ratelimit"
+	"HarborArk/internal/response"
+	"HarborArk/internal/service/audit"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// routeLimiter 路由分组的限流器
+type routeLimiter struct {
+	path    string
+	byUser  bool
+	limiter *ratelimit.Limiter
+}
+
+// rateLimiters 按当前配置创建的限流器，配置变化时整体替换
+type rateLimiters struct {
+	enabled bool
+	ip      *ratelimit.Limiter
+	user    *ratelimit.Limiter
+	routes  []routeLimiter
+	exempt  []*net.IPNet
+}
+
+var limiters atomic.Pointer[rateLimiters]
+
+// SetRateLimits 按配置重建限流器并更新登录失败锁定的参数。
+// 限流计数随之清零，已有的登录失败记录与锁定保持不变
+func SetRateLimits(cfg config.RateLimitConfig) {
+	exempt, err := ratelimit.ParseNetworks(cfg.Exempt)
+	if err != nil {
+		logging.Named("ratelimit").Error("限流白名单无效，已忽略", zap.Error(err))
+	}
+	l := &rateLimiters{
+		enabled: cfg.Enabled,
+		ip:      ratelimit.NewLimiter(cfg.IP),
+		user:    ratelimit.NewLimiter(cfg.User),
+		exempt:  exempt,
+	}
+	for _, route := range cfg.Routes {
+		l.routes = append(l.routes, routeLimiter{
+			path:    route.Path,
+			byUser:  route.Key == "user",
+			limiter: ratelimit.NewLimiter(route.Rule()),
+		})
+	}
+	limiters.Store(l)
+	ratelimit.Lockouts().Configure(cfg.Lockout)
+}
+
+// limitCheck 一个请求需要满足的一项限额
+type limitCheck struct {
+	scope   string
+	key     string
+	limiter *ratelimit.Limiter
+}
+
+// checks 返回请求需要满足的各项限额，未配置的限额不在其中
+func (l *rateLimiters) checks(ip, user, path string) []limitCheck {
+	ipKey := ratelimit.IPKey(ip)
+	checks := make([]limitCheck, 0, 3)
+	if l.ip != nil {
+		checks = append(checks, limitCheck{scope: "ip", key: ipKey, limiter: l.ip})
+	}
+	if l.user != nil && user != "" {
+		checks = append(checks, limitCheck{scope: "user", key: ratelimit.UserKey(user), limiter: l.user})
+	}
+	for _, route := range l.routes {
+		if !matchPath(route.path, path) {
+			continue
+		}
+		if route.limiter != nil {
+			key := ipKey
+			if route.byUser && user != "" {
+				key = ratelimit.UserKey(user)
+			}
+			checks = append(checks, limitCheck{scope: route.path, key: key, limiter: route.limiter})
+		}
+		break
+	}
+	return checks
+}
+
+// ceilSeconds 返回向上取整的秒数，用于 Retry-After 与 RateLimit-Reset
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// RateLimit 按客户端 IP、已认证用户与路由分组的令牌桶限流，请求需同时满足各项限额。
+// 响应中的 RateLimit-* 头描述剩余令牌最少的一项，超出限额时返回 429 与 Retry-After。
+// 需放在认证相关中间件之后，以便按用户计数
+func RateLimit(cfg config.RateLimitConfig) gin.HandlerFunc {
+	SetRateLimits(cfg)
+
+	return func(c *gin.Context) {
+		l := limiters.Load()
+		ip := c.ClientIP()
+		if !l.enabled || ratelimit.Contains(l.exempt, ip) {
+			c.Next()
+			return
+		}
+
+		checks := l.checks(ip, CurrentUser(c), c.Request.URL.Path)
+		if len(checks) == 0 {
+			c.Next()
+			return
+		}
+		var (
+			result   ratelimit.Result
+			policies = make([]string, 0, len(checks))
+			rejected string
+		)
+		for i, check := range checks {
+			res := check.limiter.Allow(check.key)
+			policies = append(policies, check.limiter.Policy())
+			if !res.Allowed {
+				// 已被拒绝时不再消耗其余限额的令牌
+				result, rejected = res, check.scope
+				break
+			}
+			if i == 0 || res.Remaining < result.Remaining {
+				result = res
+			}
+		}
+
+		c.Header("RateLimit-Policy", strings.Join(policies, ", "))
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+		if rejected == "" {
+			c.Next()
+			return
+		}
+
+		retry := max(ceilSeconds(result.RetryAfter), 1)
+		metrics.AddRateLimited(rejected)
+		logging.Ctx(c.Request.Context()).Debug("请求超出限额",
+			zap.String("scope", rejected), zap.String("ip", ip), zap.String("user", CurrentUser(c)), zap.Int("retry_after", retry))
+		c.Header("Retry-After", strconv.Itoa(retry))
+		response.Fail(c, response.TooManyRequests(i18n.Ctx(c.Request.Context()).T("api.ratelimit.exceeded", retry)))
+	}
+}
+
+// loginKeys 返回登录失败锁定的计数键：用户名与客户端 IP
+func loginKeys(c *gin.Context, user string) []string {
+	keys := []string{ratelimit.IPKey(c.ClientIP())}
+	if user != "" {
+		keys = append(keys, ratelimit.UserKey(user))
+	}
+	return keys
+}
+
+// LoginLocked 供登录接口在校验密码前调用，用户或客户端 IP 已被锁定时返回 429 并返回 true
+func LoginLocked(c *gin.Context, user string) bool {
+	wait := ratelimit.Lockouts().Locked(loginKeys(c, user)...)
+	if wait <= 0 {
+		return false
+	}
+	retry := max(ceilSeconds(wait), 1)
+	c.Header("Retry-After", strconv.Itoa(retry))
+	response.Fail(c, response.TooManyRequests(i18n.Ctx(c.Request.Context()).T("api.auth.locked", retry)))
+	return true
+}
+
+// LoginFailed 记录一次登录失败，用户或客户端 IP 的失败次数达到上限时锁定并写入审计日志
+func LoginFailed(c *gin.Context, user string) {
+	actor := user
+	if actor == "" {
+		actor = "anonymous"
+	}
+	for _, locked := range ratelimit.Lockouts().Fail(loginKeys(c, user)...) {
+		kind, _, _ := strings.Cut(locked.Key, ":")
+		metrics.AddLockout(kind)
+		logging.Ctx(c.Request.Context()).Warn("登录失败次数过多，已锁定",
+			zap.String("key", locked.Key), zap.String("ip", c.ClientIP()), zap.Time("until", locked.LockedUntil))
+		audit.Record(c.Request.Context(), audit.Entry{
+			Actor:    actor,
+			IP:       c.ClientIP(),
+			Action:   audit.ActionLoginLocked,
+			Resource: locked.Key,
+			Result:   audit.ResultFailure,
+			Detail: map[string]string{
+				"until":    locked.LockedUntil.UTC().Format(time.RFC3339),
+				"lockouts": strconv.Itoa(locked.Lockouts),
+			},
+		})
+	}
+}
+
+// LoginSucceeded 登录成功，清除该用户的失败记录。
+// 客户端 IP 的失败记录保留，避免攻击者用自己的账号登录来重置对其他账号的猜测次数
+func LoginSucceeded(c *gin.Context, user string) {
+	ratelimit.Lockouts().Succeed(ratelimit.UserKey(user))
+}
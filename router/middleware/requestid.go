@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"HarborArk/internal/logging"
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// HeaderRequestID 请求 ID 的请求头与响应头
+	HeaderRequestID = "X-Request-ID"
+	// ContextRequestIDKey gin.Context 中保存请求 ID 的键
+	ContextRequestIDKey = "request_id"
+)
+
+// requestIDPattern 接受客户端或反向代理传入的请求 ID 的格式，其他值重新生成，避免日志注入
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestID 为每个请求分配请求 ID：沿用合法的 X-Request-ID 请求头，否则生成新的 ID。
+// 请求 ID 写入响应头、gin.Context、请求 context 中的日志器以及链路追踪的 span
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(HeaderRequestID)
+		if !requestIDPattern.MatchString(id) {
+			id = newRequestID()
+		}
+		c.Set(ContextRequestIDKey, id)
+		c.Header(HeaderRequestID, id)
+
+		ctx := logging.WithRequestID(c.Request.Context(), id)
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String("http.request_id", id))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// GetRequestID 获取当前请求的 ID
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(ContextRequestIDKey)
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"HarborArk/config"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// securityHeaders 按当前配置生成的安全响应头
+type securityHeaders struct {
+	enabled bool
+	headers map[string]string
+	// hsts 只在 HTTPS 请求中发送
+	hsts string
+}
+
+var secHeaders atomic.Pointer[securityHeaders]
+
+// SetSecurity 更新 CORS、安全响应头与 CSRF 防护的配置，对之后的请求立即生效
+func SetSecurity(cfg config.SecurityConfig) {
+	setCORS(cfg.CORS)
+	setSecurityHeaders(cfg.Headers)
+	setCSRF(cfg.CSRF)
+}
+
+// setSecurityHeaders 预先生成安全响应头
+func setSecurityHeaders(cfg config.SecurityHeadersConfig) {
+	h := &securityHeaders{enabled: cfg.Enabled, headers: make(map[string]string)}
+	if cfg.ContentSecurityPolicy != "" {
+		h.headers["Content-Security-Policy"] = cfg.ContentSecurityPolicy
+	}
+	if cfg.FrameOptions != "" {
+		h.headers["X-Frame-Options"] = cfg.FrameOptions
+	}
+	if cfg.ReferrerPolicy != "" {
+		h.headers["Referrer-Policy"] = cfg.ReferrerPolicy
+	}
+	if cfg.ContentTypeNosniff {
+		h.headers["X-Content-Type-Options"] = "nosniff"
+	}
+	if cfg.HSTSMaxAge > 0 {
+		h.hsts = "max-age=" + strconv.Itoa(int(cfg.HSTSMaxAge.Seconds()))
+		if cfg.HSTSIncludeSubdomains {
+			h.hsts += "; includeSubDomains"
+		}
+		if cfg.HSTSPreload {
+			h.hsts += "; preload"
+		}
+	}
+	secHeaders.Store(h)
+}
+
+// SecurityHeaders 为全部响应添加 Content-Security-Policy、X-Frame-Options 等安全响应头，
+// Strict-Transport-Security 只在 HTTPS 请求中发送。处理器可以覆盖这些响应头，如 Swagger 文档页面的 CSP
+func SecurityHeaders(cfg config.SecurityHeadersConfig) gin.HandlerFunc {
+	setSecurityHeaders(cfg)
+
+	return func(c *gin.Context) {
+		h := secHeaders.Load()
+		if h.enabled {
+			for name, value := range h.headers {
+				c.Header(name, value)
+			}
+			if h.hsts != "" && c.Request.TLS != nil {
+				c.Header("Strict-Transport-Security", h.hsts)
+			}
+		}
+		c.Next()
+	}
+}
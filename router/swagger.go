@@ -3,6 +3,8 @@ package router
 import (
 	"HarborArk/cmd/docs"
 	"HarborArk/config"
+	"HarborArk/internal/i18n"
+	"HarborArk/internal/response"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -10,14 +12,10 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-// SetupSwagger 设置 Swagger 路由
+// SetupSwagger 设置 Swagger 路由。路由始终注册，swagger.enabled 支持热加载，关闭时返回 404
 func SetupSwagger(r *gin.Engine) {
 	swaggerConfig := config.GetSwaggerConfig()
 
-	if !swaggerConfig.Enabled {
-		return
-	}
-
 	// 设置 Swagger 信息
 	docs.SwaggerInfo.Title = swaggerConfig.Title
 	docs.SwaggerInfo.Description = swaggerConfig.Description
@@ -26,16 +24,19 @@ func SetupSwagger(r *gin.Engine) {
 	docs.SwaggerInfo.BasePath = swaggerConfig.BasePath
 	docs.SwaggerInfo.Schemes = []string{"http", "https"}
 
+	docsGroup := r.Group("", swaggerEnabled, swaggerCSP)
+
 	// Swagger UI 路由
-	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	docsGroup.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	// API 文档重定向
-	r.GET("/docs", func(c *gin.Context) {
+	docsGroup.GET("/docs", func(c *gin.Context) {
 		c.Redirect(http.StatusMovedPermanently, "/swagger/index.html")
 	})
 
 	// API 信息端点
-	r.GET("/api/info", func(c *gin.Context) {
+	docsGroup.GET("/api/info", func(c *gin.Context) {
+		swaggerConfig := config.GetSwaggerConfig()
 		c.JSON(http.StatusOK, gin.H{
 			"title":       swaggerConfig.Title,
 			"description": swaggerConfig.Description,
@@ -44,3 +45,23 @@ func SetupSwagger(r *gin.Engine) {
 		})
 	})
 }
+
+// docsContentSecurityPolicy Swagger UI 页面使用内联脚本、样式与 data: 图片
+const docsContentSecurityPolicy = "default-src 'self'; script-src 'self' 'unsafe-inline'; style-src 'self' 'unsafe-inline'; img-src 'self' data:; frame-ancestors 'none'"
+
+// swaggerCSP 启用了 Content-Security-Policy 时，文档页面改用允许内联脚本的策略
+func swaggerCSP(c *gin.Context) {
+	if c.Writer.Header().Get("Content-Security-Policy") != "" {
+		c.Header("Content-Security-Policy", docsContentSecurityPolicy)
+	}
+	c.Next()
+}
+
+// swaggerEnabled 按当前配置决定是否提供文档
+func swaggerEnabled(c *gin.Context) {
+	if !config.GetSwaggerConfig().Enabled {
+		response.Fail(c, response.NotFound(i18n.Ctx(c.Request.Context()).T("api.error.route_not_found")))
+		return
+	}
+	c.Next()
+}